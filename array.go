@@ -0,0 +1,445 @@
+package typx
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Array is a PostgreSQL array of T. A nil Array represents SQL NULL, while an empty one represents '{}'.
+// Use Nil[T] elements for arrays that may contain NULL, and nested Arrays for multi-dimensional arrays,
+// e.g. Array[Nil[string]] for text[] or Array[Array[int]] for int[][].
+// It implements interfaces for SQL (using the array text format), JSON and BSON encoding.
+type Array[T any] []T
+
+// pgArrayValuer is implemented by Array[T] so that nested arrays are encoded as sub-arrays.
+type pgArrayValuer interface {
+	appendPGArray(buf []byte) ([]byte, error)
+}
+
+// pgArrayScanner is implemented by *Array[T] so that nested arrays are decoded from sub-arrays.
+type pgArrayScanner interface {
+	scanPGArray(node pgArrayNode) error
+}
+
+// nullableTarget is implemented by *Nil[T] so that array elements can be decoded into it.
+type nullableTarget interface{ nilTarget() any }
+
+// Scan implements the sql.Scanner interface for the PostgreSQL array text format,
+// including quoted and escaped elements, NULL elements and multi-dimensional arrays.
+func (a *Array[T]) Scan(src any) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return fmt.Errorf("cannot scan %T into Array: expected the array text format ([]byte or string)", src)
+	}
+	node, err := parsePGArray(text)
+	if err != nil {
+		return err
+	}
+	return a.scanPGArray(node)
+}
+
+func (a *Array[T]) scanPGArray(node pgArrayNode) error {
+	if !node.array {
+		return errors.New("cannot scan Array: expected a sub-array, got an element")
+	}
+	arr := make(Array[T], len(node.elems))
+	for i, elem := range node.elems {
+		if err := scanPGElem(reflect.ValueOf(&arr[i]).Elem(), elem); err != nil {
+			return fmt.Errorf("cannot scan Array element %d: %w", i, err)
+		}
+	}
+	*a = arr
+	return nil
+}
+
+// Value implements the driver.Valuer interface, returning the array text format.
+func (a Array[T]) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	buf, err := a.appendPGArray(nil)
+	if err != nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+func (a Array[T]) appendPGArray(buf []byte) ([]byte, error) {
+	buf = append(buf, '{')
+	for i := range a {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		var err error
+		if buf, err = appendPGElem(buf, reflect.ValueOf(a[i]), appendPGArrayText); err != nil {
+			return nil, fmt.Errorf("cannot encode Array element %d: %w", i, err)
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. A nil Array is encoded as null.
+func (a Array[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]T(a))
+}
+
+// jsonSchema implements the schemaProvider interface, describing an array of T that may be null.
+func (a Array[T]) jsonSchema(of func(reflect.Type) (*Schema, error)) (*Schema, error) {
+	items, err := of(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	return nullableSchema(&Schema{Type: SchemaType{"array"}, Items: items}), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (a *Array[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*[]T)(a))
+}
+
+// MarshalBSONValue implements the bson.ValueMarshaler interface. A nil Array is encoded as null.
+func (a Array[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if a == nil {
+		return bson.TypeNull, nil, nil
+	}
+	return bson.MarshalValue([]T(a))
+}
+
+// UnmarshalBSONValue implements the bson.ValueUnmarshaler interface.
+func (a *Array[T]) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bson.TypeNull {
+		*a = nil
+		return nil
+	}
+	var arr []T
+	if err := bson.UnmarshalValue(t, data, &arr); err != nil {
+		return err
+	}
+	if arr == nil {
+		arr = []T{}
+	}
+	*a = arr
+	return nil
+}
+
+// appendPGElem appends the text representation of a scalar or nested array value,
+// using quote to append text that may need quoting.
+func appendPGElem(buf []byte, v reflect.Value, quote func([]byte, string) []byte) ([]byte, error) {
+	if nested, ok := v.Interface().(pgArrayValuer); ok {
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return append(buf, "NULL"...), nil
+		}
+		return nested.appendPGArray(buf)
+	}
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		val, err := valuer.Value()
+		if err != nil {
+			return nil, err
+		}
+		switch val := val.(type) {
+		case nil:
+			return append(buf, "NULL"...), nil
+		case []byte:
+			// Valuers such as Dyn return textual []byte representations rather than bytea.
+			return quote(buf, string(val)), nil
+		}
+		return appendPGElem(buf, reflect.ValueOf(val), quote)
+	}
+	switch val := v.Interface().(type) {
+	case time.Time:
+		return quote(buf, val.Format(time.RFC3339Nano)), nil
+	case encoding.TextMarshaler:
+		text, err := val.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return quote(buf, string(text)), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return quote(buf, v.String()), nil
+	case reflect.Bool:
+		return strconv.AppendBool(buf, v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(buf, v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(buf, v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(buf, v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return append(buf, "NULL"...), nil
+		}
+		return appendPGElem(buf, v.Elem(), quote)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.IsNil() {
+				return append(buf, "NULL"...), nil
+			}
+			return quote(buf, `\x`+hex.EncodeToString(v.Bytes())), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported element type %s", v.Type())
+}
+
+// appendPGArrayText appends s, quoting and escaping it if needed.
+func appendPGArrayText(buf []byte, s string) []byte {
+	if s != "" && !strings.EqualFold(s, "NULL") && !strings.ContainsAny(s, "{}\",\\ \t\n\r\v\f") {
+		return append(buf, s...)
+	}
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			buf = append(buf, '\\')
+		}
+		buf = append(buf, s[i])
+	}
+	return append(buf, '"')
+}
+
+// pgArrayNode is a parsed array or element. A nil text of an element means NULL.
+type pgArrayNode struct {
+	array bool
+	elems []pgArrayNode
+	text  *string
+}
+
+// parsePGArray parses the PostgreSQL array text format, e.g. {a,"b c",NULL} or [0:1]={{1},{2}}.
+func parsePGArray(s string) (pgArrayNode, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") {
+		// Skip the optional dimension decoration.
+		i := strings.IndexByte(s, '=')
+		if i < 0 {
+			return pgArrayNode{}, fmt.Errorf("invalid array literal %q: missing '=' after dimensions", s)
+		}
+		s = strings.TrimSpace(s[i+1:])
+	}
+	p := pgArrayParser{s: s}
+	node, err := p.array(0)
+	if err != nil {
+		return pgArrayNode{}, err
+	}
+	p.skipSpace()
+	if p.i != len(p.s) {
+		return pgArrayNode{}, fmt.Errorf("invalid array literal: unexpected %q at offset %d", p.s[p.i:], p.i)
+	}
+	return node, nil
+}
+
+type pgArrayParser struct {
+	s string
+	i int
+}
+
+func (p *pgArrayParser) skipSpace() {
+	for p.i < len(p.s) && strings.IndexByte(" \t\n\r\v\f", p.s[p.i]) >= 0 {
+		p.i++
+	}
+}
+
+func (p *pgArrayParser) array(depth int) (pgArrayNode, error) {
+	if depth > 64 {
+		return pgArrayNode{}, errors.New("invalid array literal: too many dimensions")
+	}
+	p.skipSpace()
+	if p.i >= len(p.s) || p.s[p.i] != '{' {
+		return pgArrayNode{}, fmt.Errorf("invalid array literal: expected '{' at offset %d", p.i)
+	}
+	p.i++
+	node := pgArrayNode{array: true}
+	p.skipSpace()
+	if p.i < len(p.s) && p.s[p.i] == '}' {
+		p.i++
+		return node, nil
+	}
+	for {
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return pgArrayNode{}, errors.New("invalid array literal: unexpected end")
+		}
+		var elem pgArrayNode
+		var err error
+		if p.s[p.i] == '{' {
+			elem, err = p.array(depth + 1)
+		} else {
+			elem, err = p.element()
+		}
+		if err != nil {
+			return pgArrayNode{}, err
+		}
+		node.elems = append(node.elems, elem)
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return pgArrayNode{}, errors.New("invalid array literal: unexpected end")
+		}
+		switch p.s[p.i] {
+		case ',':
+			p.i++
+		case '}':
+			p.i++
+			return node, nil
+		default:
+			return pgArrayNode{}, fmt.Errorf("invalid array literal: unexpected %q at offset %d", p.s[p.i], p.i)
+		}
+	}
+}
+
+func (p *pgArrayParser) element() (pgArrayNode, error) {
+	var b strings.Builder
+	if p.s[p.i] == '"' {
+		p.i++
+		for {
+			if p.i >= len(p.s) {
+				return pgArrayNode{}, errors.New("invalid array literal: unterminated quoted element")
+			}
+			c := p.s[p.i]
+			p.i++
+			switch c {
+			case '"':
+				text := b.String()
+				return pgArrayNode{text: &text}, nil
+			case '\\':
+				if p.i >= len(p.s) {
+					return pgArrayNode{}, errors.New("invalid array literal: unterminated escape")
+				}
+				c = p.s[p.i]
+				p.i++
+			}
+			b.WriteByte(c)
+		}
+	}
+	escaped := false
+	for p.i < len(p.s) && p.s[p.i] != ',' && p.s[p.i] != '}' {
+		c := p.s[p.i]
+		p.i++
+		switch c {
+		case '{', '"':
+			return pgArrayNode{}, fmt.Errorf("invalid array literal: unexpected %q at offset %d", c, p.i-1)
+		case '\\':
+			if p.i >= len(p.s) {
+				return pgArrayNode{}, errors.New("invalid array literal: unterminated escape")
+			}
+			c = p.s[p.i]
+			p.i++
+			escaped = true
+		}
+		b.WriteByte(c)
+	}
+	text := strings.TrimRight(b.String(), " \t\n\r\v\f")
+	if !escaped && strings.EqualFold(text, "NULL") {
+		return pgArrayNode{}, nil
+	}
+	return pgArrayNode{text: &text}, nil
+}
+
+// scanPGElem stores a parsed element into v. A nil text means NULL.
+func scanPGElem(v reflect.Value, node pgArrayNode) error {
+	ptr := v.Addr().Interface()
+	if scanner, ok := ptr.(pgArrayScanner); ok {
+		if !node.array && node.text == nil {
+			v.SetZero()
+			return nil
+		}
+		return scanner.scanPGArray(node)
+	}
+	if node.array {
+		return fmt.Errorf("cannot scan a sub-array into %s", v.Type())
+	}
+	if nullable, ok := ptr.(nullableTarget); ok {
+		v.SetZero()
+		if node.text == nil {
+			return nil
+		}
+		return scanPGElem(reflect.ValueOf(nullable.nilTarget()).Elem(), node)
+	}
+	if scanner, ok := ptr.(sql.Scanner); ok {
+		if node.text == nil {
+			return scanner.Scan(nil)
+		}
+		return scanner.Scan(*node.text)
+	}
+	if node.text == nil {
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+			v.SetZero()
+			return nil
+		}
+		return fmt.Errorf("cannot scan NULL into %s: use Nil[%s] elements for nullable arrays", v.Type(), v.Type())
+	}
+	return parsePGText(v, *node.text)
+}
+
+// parsePGText parses the text representation of a scalar PostgreSQL value into v.
+func parsePGText(v reflect.Value, s string) error {
+	switch ptr := v.Addr().Interface().(type) {
+	case *time.Time:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05.999999999Z07", "2006-01-02 15:04:05.999999999", "2006-01-02"} {
+			if t, err := time.Parse(layout, s); err == nil {
+				*ptr = t
+				return nil
+			}
+		}
+		return fmt.Errorf("cannot parse %q as time", s)
+	case encoding.TextUnmarshaler:
+		return ptr.UnmarshalText([]byte(s))
+	}
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, v.Type().Bits())
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, v.Type().Bits())
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, v.Type().Bits())
+		v.SetFloat(f)
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		return parsePGText(v.Elem(), s)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("cannot parse an element into %s", v.Type())
+		}
+		data := []byte(s)
+		if strings.HasPrefix(s, `\x`) {
+			if data, err = hex.DecodeString(s[2:]); err != nil {
+				return err
+			}
+		}
+		v.SetBytes(data)
+	default:
+		return fmt.Errorf("cannot parse an element into %s", v.Type())
+	}
+	return err
+}
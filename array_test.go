@@ -0,0 +1,126 @@
+package typx_test
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_Array_Scan(t *testing.T) {
+	var strs typx.Array[string]
+	assert.NoError(t, strs.Scan([]byte(`{a,"b c","with \"quote\"","back\\slash","",null_ish,"NULL"}`)))
+	assert.Equal(t, typx.Array[string]{"a", "b c", `with "quote"`, `back\slash`, "", "null_ish", "NULL"}, strs)
+
+	var nils typx.Array[typx.Nil[string]]
+	assert.NoError(t, nils.Scan(`{a,NULL, b }`))
+	assert.Equal(t, typx.Array[typx.Nil[string]]{typx.NilFrom("a"), {}, typx.NilFrom("b")}, nils)
+
+	var grid typx.Array[typx.Array[typx.Nil[int]]]
+	assert.NoError(t, grid.Scan(`[1:2][1:2]={{1,2},{NULL,4}}`))
+	assert.Equal(t, typx.Array[typx.Array[typx.Nil[int]]]{{typx.NilFrom(1), typx.NilFrom(2)}, {{}, typx.NilFrom(4)}}, grid)
+
+	var empty typx.Array[int]
+	assert.NoError(t, empty.Scan(`{}`))
+	assert.Equal(t, typx.Array[int]{}, empty)
+
+	var null typx.Array[int]
+	assert.NoError(t, null.Scan(nil))
+	assert.Nil(t, null)
+
+	id := uuid.New()
+	var ids typx.Array[uuid.UUID]
+	assert.NoError(t, ids.Scan(`{`+id.String()+`}`))
+	assert.Equal(t, typx.Array[uuid.UUID]{id}, ids)
+
+	var misc typx.Array[typx.Nil[time.Time]]
+	assert.NoError(t, misc.Scan(`{"2024-01-02 03:04:05+00",NULL}`))
+	assert.True(t, misc[0].Val.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	assert.False(t, misc[1].NotNil)
+
+	var blobs typx.Array[[]byte]
+	assert.NoError(t, blobs.Scan(`{"\\x0102",NULL}`))
+	assert.Equal(t, typx.Array[[]byte]{{1, 2}, nil}, blobs)
+
+	var bools typx.Array[bool]
+	assert.NoError(t, bools.Scan(`{t,f}`))
+	assert.Equal(t, typx.Array[bool]{true, false}, bools)
+
+	for _, invalid := range []string{`{1,NULL}`, `{1,2`, `{"a}`, `1,2`, `{{1},2}`, `{1}x`} {
+		var ints typx.Array[int]
+		assert.Error(t, ints.Scan(invalid), invalid)
+	}
+	assert.Error(t, strs.Scan(1))
+}
+
+func Test_Array_Value(t *testing.T) {
+	tests := []struct {
+		name  string
+		value driver.Valuer
+		want  any
+	}{
+		{name: "nil", value: typx.Array[string](nil), want: nil},
+		{name: "empty", value: typx.Array[string]{}, want: "{}"},
+		{name: "quoting", value: typx.Array[string]{"a", "b c", `q"b\`, "", "null"}, want: `{a,"b c","q\"b\\","","null"}`},
+		{name: "nullable", value: typx.Array[typx.Nil[int]]{typx.NilFrom(1), {}}, want: `{1,NULL}`},
+		{name: "multi-dimensional", value: typx.Array[typx.Array[float64]]{{1.5, 2}, {3, 4}}, want: `{{1.5,2},{3,4}}`},
+		{name: "bytea", value: typx.Array[[]byte]{{1, 2}}, want: `{"\\x0102"}`},
+		{name: "json", value: typx.Array[typx.Dyn]{{Val: map[string]any{"a": 1}}}, want: `{"{\"a\":1}"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.value.Value()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := typx.Array[chan int]{make(chan int)}.Value()
+	assert.Error(t, err)
+}
+
+func Test_Array_RoundTrip(t *testing.T) {
+	want := typx.Array[typx.Array[typx.Nil[string]]]{{typx.NilFrom("a,b"), {}}, {typx.NilFrom(`{"x"}`), typx.NilFrom("NULL")}}
+	value, err := want.Value()
+	assert.NoError(t, err)
+	var got typx.Array[typx.Array[typx.Nil[string]]]
+	assert.NoError(t, got.Scan(value))
+	assert.Equal(t, want, got)
+}
+
+func Test_Array_JSON(t *testing.T) {
+	data, err := json.Marshal(typx.Array[typx.Nil[int]]{typx.NilFrom(1), {}})
+	assert.NoError(t, err)
+	assert.Equal(t, `[1,null]`, string(data))
+
+	data, err = json.Marshal(typx.Array[int](nil))
+	assert.NoError(t, err)
+	assert.Equal(t, `null`, string(data))
+
+	var got typx.Array[typx.Nil[int]]
+	assert.NoError(t, json.Unmarshal([]byte(`[null,2]`), &got))
+	assert.Equal(t, typx.Array[typx.Nil[int]]{{}, typx.NilFrom(2)}, got)
+}
+
+func Test_Array_BSON(t *testing.T) {
+	type doc struct {
+		Tags typx.Array[string] `bson:"tags"`
+		None typx.Array[string] `bson:"none"`
+	}
+	data, err := bson.Marshal(doc{Tags: typx.Array[string]{"a", ""}})
+	assert.NoError(t, err)
+
+	var raw bson.M
+	assert.NoError(t, bson.Unmarshal(data, &raw))
+	assert.Equal(t, bson.A{"a", ""}, raw["tags"])
+	assert.Nil(t, raw["none"])
+
+	var got doc
+	assert.NoError(t, bson.Unmarshal(data, &got))
+	assert.Equal(t, doc{Tags: typx.Array[string]{"a", ""}}, got)
+}
@@ -0,0 +1,444 @@
+package typx
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CBOR major types as defined in RFC 8949 section 3.1.
+const (
+	cborUint   = 0 << 5
+	cborNegInt = 1 << 5
+	cborBytes  = 2 << 5
+	cborText   = 3 << 5
+	cborArray  = 4 << 5
+	cborMap    = 5 << 5
+	cborTag    = 6 << 5
+	cborSimple = 7 << 5
+)
+
+const (
+	cborTagDateTime = 0
+	cborTagEpoch    = 1
+	cborMaxDepth    = 512
+	cborIndefinite  = 31
+	cborBreak       = 0xff
+)
+
+// marshalCBOR encodes v as RFC 8949 CBOR.
+// Integers, floats, byte strings and time.Time (as tag 0) are preserved as such,
+// while other values are encoded by their JSON-shaped representation.
+func marshalCBOR(v any) ([]byte, error) {
+	return appendCBOR(nil, reflect.ValueOf(v), 0)
+}
+
+func appendCBOR(buf []byte, v reflect.Value, depth int) ([]byte, error) {
+	if depth > cborMaxDepth {
+		return nil, errors.New("cannot marshal CBOR: maximum nesting depth exceeded")
+	}
+	if !v.IsValid() {
+		return append(buf, cborSimple|22), nil
+	}
+	switch val := v.Interface().(type) {
+	case Dyn:
+		return appendCBOR(buf, reflect.ValueOf(val.Val), depth+1)
+	case time.Time:
+		buf = appendCBORHead(buf, cborTag, cborTagDateTime)
+		s := val.Format(time.RFC3339Nano)
+		return append(appendCBORHead(buf, cborText, uint64(len(s))), s...), nil
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return appendCBORInt(buf, i), nil
+		}
+		if u, err := strconv.ParseUint(string(val), 10, 64); err == nil {
+			return appendCBORHead(buf, cborUint, u), nil
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal json.Number %q as CBOR: %w", val, err)
+		}
+		return appendCBORFloat64(buf, f), nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, cborSimple|21), nil
+		}
+		return append(buf, cborSimple|20), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendCBORInt(buf, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendCBORHead(buf, cborUint, v.Uint()), nil
+	case reflect.Float32:
+		buf = append(buf, cborSimple|26)
+		return binary.BigEndian.AppendUint32(buf, math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return appendCBORFloat64(buf, v.Float()), nil
+	case reflect.String:
+		return append(appendCBORHead(buf, cborText, uint64(v.Len())), v.String()...), nil
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return append(buf, cborSimple|22), nil
+		}
+		return appendCBOR(buf, v.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return append(buf, cborSimple|22), nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			buf = appendCBORHead(buf, cborBytes, uint64(v.Len()))
+			for i := range v.Len() {
+				buf = append(buf, byte(v.Index(i).Uint()))
+			}
+			return buf, nil
+		}
+		buf = appendCBORHead(buf, cborArray, uint64(v.Len()))
+		for i := range v.Len() {
+			var err error
+			if buf, err = appendCBOR(buf, v.Index(i), depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return append(buf, cborSimple|22), nil
+		}
+		return appendCBORMap(buf, v, depth)
+	}
+	// Fall back to the JSON representation for structs and other types,
+	// which is what Dyn values carry through the other encodings as well.
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal %s as CBOR: %w", v.Type(), err)
+	}
+	// Decode numbers as json.Number, so that integers are not turned into floats.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return appendCBOR(buf, reflect.ValueOf(tree), depth+1)
+}
+
+// appendCBORMap encodes a map with its keys sorted in the core deterministic order.
+func appendCBORMap(buf []byte, v reflect.Value, depth int) ([]byte, error) {
+	type entry struct {
+		key []byte
+		val reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		// Keys are converted to strings the same way encoding/json does.
+		k := iter.Key()
+		if tm, ok := k.Interface().(encoding.TextMarshaler); ok && k.Kind() != reflect.String {
+			text, err := tm.MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			k = reflect.ValueOf(string(text))
+		}
+		switch k.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			k = reflect.ValueOf(strconv.FormatInt(k.Int(), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			k = reflect.ValueOf(strconv.FormatUint(k.Uint(), 10))
+		case reflect.String:
+		default:
+			return nil, fmt.Errorf("cannot marshal map key of type %s as CBOR", k.Type())
+		}
+		key, err := appendCBOR(nil, k, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{key: key, val: iter.Value()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		if len(a.key) != len(b.key) {
+			return len(a.key) - len(b.key)
+		}
+		return strings.Compare(string(a.key), string(b.key))
+	})
+	buf = appendCBORHead(buf, cborMap, uint64(len(entries)))
+	for _, e := range entries {
+		buf = append(buf, e.key...)
+		var err error
+		if buf, err = appendCBOR(buf, e.val, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func appendCBORHead(buf []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), n)
+	}
+}
+
+func appendCBORInt(buf []byte, i int64) []byte {
+	if i < 0 {
+		return appendCBORHead(buf, cborNegInt, uint64(-(i + 1)))
+	}
+	return appendCBORHead(buf, cborUint, uint64(i))
+}
+
+func appendCBORFloat64(buf []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(append(buf, cborSimple|27), math.Float64bits(f))
+}
+
+// unmarshalCBOR decodes a single RFC 8949 CBOR data item into a JSON-shaped tree.
+// Integers become int64 (or uint64 when they overflow int64), floats become float64,
+// byte strings become []byte, text strings become string, arrays become []any,
+// maps become map[string]any and tags 0 and 1 become time.Time.
+// Other tags are ignored and their content is decoded as is.
+func unmarshalCBOR(data []byte) (any, error) {
+	d := cborDecoder{data: data}
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.off != len(d.data) {
+		return nil, fmt.Errorf("cannot unmarshal CBOR: %d trailing bytes", len(d.data)-d.off)
+	}
+	return v, nil
+}
+
+type cborDecoder struct {
+	data []byte
+	off  int
+}
+
+var errCBORTruncated = errors.New("cannot unmarshal CBOR: unexpected end of data")
+
+func (d *cborDecoder) byte() (byte, error) {
+	if d.off >= len(d.data) {
+		return 0, errCBORTruncated
+	}
+	b := d.data[d.off]
+	d.off++
+	return b, nil
+}
+
+func (d *cborDecoder) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.off) {
+		return nil, errCBORTruncated
+	}
+	b := d.data[d.off : d.off+int(n)]
+	d.off += int(n)
+	return b, nil
+}
+
+// head reads the initial byte and argument of a data item.
+// For indefinite lengths it returns indefinite set to true.
+func (d *cborDecoder) head() (major byte, info byte, arg uint64, indefinite bool, err error) {
+	b, err := d.byte()
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	major, info = b&0xe0, b&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), false, nil
+	case info <= 27:
+		raw, err := d.next(1 << (info - 24))
+		if err != nil {
+			return 0, 0, 0, false, err
+		}
+		for _, c := range raw {
+			arg = arg<<8 | uint64(c)
+		}
+		return major, info, arg, false, nil
+	case info == cborIndefinite && major != cborUint && major != cborNegInt && major != cborTag:
+		return major, info, 0, true, nil
+	}
+	return 0, 0, 0, false, fmt.Errorf("cannot unmarshal CBOR: invalid additional information %d for major type %d", info, major>>5)
+}
+
+func (d *cborDecoder) decode(depth int) (any, error) {
+	if depth > cborMaxDepth {
+		return nil, errors.New("cannot unmarshal CBOR: maximum nesting depth exceeded")
+	}
+	major, info, arg, indefinite, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		if arg > math.MaxInt64 {
+			return arg, nil
+		}
+		return int64(arg), nil
+	case cborNegInt:
+		if arg > math.MaxInt64 {
+			return nil, fmt.Errorf("cannot unmarshal CBOR: negative integer -1-%d overflows int64", arg)
+		}
+		return -1 - int64(arg), nil
+	case cborBytes, cborText:
+		s, err := d.string(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborText {
+			return string(s), nil
+		}
+		return s, nil
+	case cborArray:
+		arr := make([]any, 0, min(arg, uint64(len(d.data)-d.off)))
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, item)
+		}
+		return arr, nil
+	case cborMap:
+		m := make(map[string]any, min(arg, uint64(len(d.data)-d.off)/2))
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			k, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("cannot unmarshal CBOR: unsupported map key of type %T, expected text string", k)
+			}
+			if m[key], err = d.decode(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case cborTag:
+		content, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return cborTagged(arg, content)
+	}
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return cborHalfFloat(uint16(arg)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(arg))), nil
+	case 27:
+		return math.Float64frombits(arg), nil
+	}
+	if indefinite {
+		return nil, errors.New("cannot unmarshal CBOR: unexpected break")
+	}
+	return nil, fmt.Errorf("cannot unmarshal CBOR: unsupported simple value %d", arg)
+}
+
+// atBreak consumes and reports a break stop code if it is next.
+func (d *cborDecoder) atBreak() bool {
+	if d.off < len(d.data) && d.data[d.off] == cborBreak {
+		d.off++
+		return true
+	}
+	return false
+}
+
+// string reads a definite or indefinite length byte or text string.
+func (d *cborDecoder) string(major byte, arg uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		s, err := d.next(arg)
+		if err != nil {
+			return nil, err
+		}
+		return slices.Clone(s), nil
+	}
+	s := []byte{}
+	for !d.atBreak() {
+		chunkMajor, _, n, chunkIndefinite, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || chunkIndefinite {
+			return nil, errors.New("cannot unmarshal CBOR: invalid indefinite length string chunk")
+		}
+		chunk, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, chunk...)
+	}
+	return s, nil
+}
+
+func cborTagged(tag uint64, content any) (any, error) {
+	switch tag {
+	case cborTagDateTime:
+		s, ok := content.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot unmarshal CBOR: tag 0 expects a text string, got %T", content)
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	case cborTagEpoch:
+		switch v := content.(type) {
+		case int64:
+			return time.Unix(v, 0).UTC(), nil
+		case float64:
+			sec, frac := math.Modf(v)
+			return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+		}
+		return nil, fmt.Errorf("cannot unmarshal CBOR: tag 1 expects a number, got %T", content)
+	}
+	return content, nil
+}
+
+// cborHalfFloat converts an IEEE 754 half-precision float to float64.
+func cborHalfFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,117 @@
+package typx_test
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Dyn_CBOR_Marshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value typx.Dyn
+		want  string
+	}{
+		{name: "nil", value: typx.Dyn{Val: nil}, want: "f6"},
+		{name: "false", value: typx.Dyn{Val: false}, want: "f4"},
+		{name: "small int", value: typx.Dyn{Val: 10}, want: "0a"},
+		{name: "uint8 arg", value: typx.Dyn{Val: 100}, want: "1864"},
+		{name: "uint64 arg", value: typx.Dyn{Val: uint64(1000000000000)}, want: "1b000000e8d4a51000"},
+		{name: "negative int", value: typx.Dyn{Val: -1000}, want: "3903e7"},
+		{name: "float64", value: typx.Dyn{Val: 1.1}, want: "fb3ff199999999999a"},
+		{name: "float32", value: typx.Dyn{Val: float32(100000)}, want: "fa47c35000"},
+		{name: "text", value: typx.Dyn{Val: "IETF"}, want: "6449455446"},
+		{name: "bytes", value: typx.Dyn{Val: []byte{1, 2, 3, 4}}, want: "4401020304"},
+		{name: "array", value: typx.Dyn{Val: []any{1, []int{2, 3}}}, want: "8201820203"},
+		{name: "sorted map", value: typx.Dyn{Val: map[string]any{"bb": 2, "a": 1}}, want: "a261610162626202"},
+		{name: "time", value: typx.Dyn{Val: time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)}, want: "c074323031332d30332d32315432303a30343a30305a"},
+		{name: "nested dyn", value: typx.Dyn{Val: typx.Dyn{Val: "a"}}, want: "6161"},
+		{name: "struct", value: typx.Dyn{Val: struct {
+			A int `json:"a"`
+		}{A: 1}}, want: "a1616101"},
+		{name: "struct with float and uint64", value: typx.Dyn{Val: struct {
+			F float64 `json:"f"`
+			U uint64  `json:"u"`
+		}{F: 1.5, U: math.MaxUint64}}, want: "a26166fb3ff800000000000061751bffffffffffffffff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.value.MarshalCBOR()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, hex.EncodeToString(got))
+		})
+	}
+}
+
+func Test_Dyn_CBOR_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    typx.Dyn
+		wantErr bool
+	}{
+		{name: "null", value: "f6", want: typx.Dyn{Val: nil}},
+		{name: "undefined", value: "f7", want: typx.Dyn{Val: nil}},
+		{name: "true", value: "f5", want: typx.Dyn{Val: true}},
+		{name: "int", value: "1903e8", want: typx.Dyn{Val: int64(1000)}},
+		{name: "negative int", value: "3863", want: typx.Dyn{Val: int64(-100)}},
+		{name: "large uint", value: "1bffffffffffffffff", want: typx.Dyn{Val: uint64(math.MaxUint64)}},
+		{name: "half float", value: "f93e00", want: typx.Dyn{Val: 1.5}},
+		{name: "float32", value: "fa47c35000", want: typx.Dyn{Val: float64(100000)}},
+		{name: "float64", value: "fb3ff199999999999a", want: typx.Dyn{Val: 1.1}},
+		{name: "bytes", value: "4401020304", want: typx.Dyn{Val: []byte{1, 2, 3, 4}}},
+		{name: "indefinite bytes", value: "5f42010243030405ff", want: typx.Dyn{Val: []byte{1, 2, 3, 4, 5}}},
+		{name: "indefinite text", value: "7f657374726561646d696e67ff", want: typx.Dyn{Val: "streaming"}},
+		{name: "array", value: "83010203", want: typx.Dyn{Val: []any{int64(1), int64(2), int64(3)}}},
+		{name: "indefinite array", value: "9f018202039f0405ffff", want: typx.Dyn{Val: []any{int64(1), []any{int64(2), int64(3)}, []any{int64(4), int64(5)}}}},
+		{name: "map", value: "a26161016162820203", want: typx.Dyn{Val: map[string]any{"a": int64(1), "b": []any{int64(2), int64(3)}}}},
+		{name: "indefinite map", value: "bf6346756ef563416d7421ff", want: typx.Dyn{Val: map[string]any{"Fun": true, "Amt": int64(-2)}}},
+		{name: "date time", value: "c074323031332d30332d32315432303a30343a30305a", want: typx.Dyn{Val: time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)}},
+		{name: "epoch", value: "c11a514b67b0", want: typx.Dyn{Val: time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)}},
+		{name: "unknown tag", value: "d74401020304", want: typx.Dyn{Val: []byte{1, 2, 3, 4}}},
+		{name: "self describe", value: "d9d9f7f5", want: typx.Dyn{Val: true}},
+		{name: "integer map key", value: "a10102", wantErr: true},
+		{name: "truncated", value: "8301", wantErr: true},
+		{name: "huge length", value: "5bffffffffffffffff", wantErr: true},
+		{name: "trailing bytes", value: "0101", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.value)
+			assert.NoError(t, err)
+			got := typx.Dyn{}
+			err = got.UnmarshalCBOR(data)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_DynCBOR_Binary(t *testing.T) {
+	value := typx.DynCBOR{Dyn: typx.Dyn{Val: map[string]any{
+		"int":   int64(42),
+		"float": 42.0,
+		"bytes": []byte("raw"),
+		"time":  time.Date(2025, 12, 25, 10, 30, 0, 123, time.UTC),
+		"list":  []any{"a", nil, false},
+	}}}
+	data, err := value.MarshalBinary()
+	assert.NoError(t, err)
+
+	got := typx.DynCBOR{}
+	assert.NoError(t, got.UnmarshalBinary(data))
+	assert.Equal(t, value, got)
+
+	_, err = value.Dyn.MarshalBinary()
+	assert.Error(t, err)
+	assert.Error(t, new(typx.Dyn).UnmarshalBinary(data))
+}
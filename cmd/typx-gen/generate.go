@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	typxPath        = "github.com/pedramktb/go-typx"
+	generatedHeader = "// Code generated by typx-gen; DO NOT EDIT."
+)
+
+// model is a parsed model struct.
+type model struct {
+	name   string
+	fields []field
+}
+
+// field is an exported field of a model.
+type field struct {
+	name     string
+	typ      string // the type of the field as written in the source
+	isOpt    bool   // whether the field is already an Opt, in which case it is not wrapped again
+	val      string // the type of the value of the DTO field, i.e. typ or the type argument of the Opt typ
+	jsonName string
+	jsonSkip bool // whether the field is tagged `json:"-"`, in which case it is only kept with the include option
+	readonly bool
+}
+
+// generate parses the non-test Go files of dir and returns the formatted source of the DTOs of the named models.
+func generate(dir string, typeNames []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var pkgName string
+	models := map[string]model{}
+	imports := map[string]string{} // import path -> name used in the generated file
+	typxName := ""
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if isGenerated(file) {
+			continue
+		}
+		pkgName = file.Name.Name
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if !slices.Contains(typeNames, ts.Name.Name) {
+					continue
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || ts.TypeParams != nil {
+					return nil, fmt.Errorf("%s: %s is not a non-generic struct type", fset.Position(ts.Pos()), ts.Name.Name)
+				}
+				fileImports := importNames(file)
+				fileTypx := fileImports[typxPath]
+				if fileTypx == "" {
+					fileTypx = "typx"
+				}
+				if typxName != "" && typxName != fileTypx {
+					return nil, fmt.Errorf("%s: typx is imported as both %s and %s", fset.Position(ts.Pos()), typxName, fileTypx)
+				}
+				typxName = fileTypx
+				m, err := parseModel(fset, ts.Name.Name, st, typxName, fileImports, imports)
+				if err != nil {
+					return nil, err
+				}
+				models[m.name] = m
+			}
+		}
+	}
+	if typxName == "" {
+		typxName = "typx"
+	}
+	imports[typxPath] = typxName
+	imports["encoding/json"] = "json"
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n\npackage %s\n\nimport (\n", generatedHeader, pkgName)
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if name := imports[path]; name != guessPackageName(path) {
+			fmt.Fprintf(&b, "\t%s %q\n", name, path)
+		} else {
+			fmt.Fprintf(&b, "\t%q\n", path)
+		}
+	}
+	b.WriteString(")\n")
+	for _, name := range typeNames {
+		m, ok := models[name]
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in %s", name, dir)
+		}
+		writeDTO(&b, m, "Create", "creating", typxName, false)
+		writeDTO(&b, m, "Update", "updating", typxName, true)
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, b.Bytes())
+	}
+	return src, nil
+}
+
+func isGenerated(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if c.Text == generatedHeader {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// importNames maps the import paths of file to the names they are referred to by.
+func importNames(file *ast.File) map[string]string {
+	names := map[string]string{}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := guessPackageName(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		names[path] = name
+	}
+	return names
+}
+
+// guessPackageName guesses the name of a package from its import path like goimports,
+// e.g. "typx" for "github.com/pedramktb/go-typx" and "yaml" for "gopkg.in/yaml.v3".
+func guessPackageName(path string) string {
+	name := filepath.Base(path)
+	name = strings.TrimPrefix(name, "go-")
+	name, _, _ = strings.Cut(name, ".")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+func parseModel(fset *token.FileSet, name string, st *ast.StructType, typxName string, fileImports, imports map[string]string) (model, error) {
+	m := model{name: name}
+	byName := map[string]string{}
+	for path, name := range fileImports {
+		byName[name] = path
+	}
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			return model{}, fmt.Errorf("%s: embedded fields are not supported", fset.Position(f.Pos()))
+		}
+		tag := reflect.StructTag("")
+		if f.Tag != nil {
+			unquoted, _ := strconv.Unquote(f.Tag.Value)
+			tag = reflect.StructTag(unquoted)
+		}
+		options := strings.Split(tag.Get("typx"), ",")
+		if slices.Contains(options, "-") {
+			continue
+		}
+		var typ bytes.Buffer
+		if err := printer.Fprint(&typ, fset, f.Type); err != nil {
+			return model{}, err
+		}
+		isOpt := isOptType(f.Type, typxName)
+		val := typ.String()
+		if isOpt {
+			var arg bytes.Buffer
+			if err := printer.Fprint(&arg, fset, f.Type.(*ast.IndexExpr).Index); err != nil {
+				return model{}, err
+			}
+			val = arg.String()
+		}
+		// Keep the imports of the packages the field type refers to.
+		ast.Inspect(f.Type, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok {
+					if path, ok := byName[ident.Name]; ok {
+						imports[path] = ident.Name
+					}
+				}
+			}
+			return true
+		})
+		jsonTag := tag.Get("json")
+		if jsonTag == "-" && !slices.Contains(options, "include") {
+			continue // not part of the JSON API unless included explicitly
+		}
+		jsonName, _, _ := strings.Cut(jsonTag, ",")
+		for _, ident := range f.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			m.fields = append(m.fields, field{
+				name:     ident.Name,
+				typ:      typ.String(),
+				isOpt:    isOpt,
+				val:      val,
+				jsonName: jsonName,
+				jsonSkip: jsonTag == "-",
+				readonly: slices.Contains(options, "readonly"),
+			})
+		}
+	}
+	return m, nil
+}
+
+func isOptType(expr ast.Expr, typxName string) bool {
+	index, ok := expr.(*ast.IndexExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := index.X.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == typxName && sel.Sel.Name == "Opt"
+}
+
+func writeDTO(b *bytes.Buffer, m model, kind, verb, typxName string, update bool) {
+	dto := kind + m.name + "DTO"
+	var fields []field
+	for _, f := range m.fields {
+		if !update || !f.readonly {
+			fields = append(fields, f)
+		}
+	}
+
+	fmt.Fprintf(b, "\n// %s is the DTO for %s %s models, where unset fields are left untouched.\ntype %s struct {\n", dto, verb, m.name, dto)
+	for _, f := range fields {
+		typ := typxName + ".Opt[" + f.typ + "]"
+		if f.isOpt {
+			typ = f.typ
+		}
+		tag := f.jsonName + ",omitzero"
+		if f.jsonSkip {
+			tag = "-"
+		}
+		fmt.Fprintf(b, "\t%s %s `json:%q`\n", f.name, typ, tag)
+	}
+	b.WriteString("}\n")
+
+	fmt.Fprintf(b, "\n// %sFrom returns the %s of m with all fields set.\nfunc %sFrom(m %s) %s {\n\treturn %s{\n", dto, dto, dto, m.name, dto, dto)
+	for _, f := range fields {
+		if f.isOpt {
+			fmt.Fprintf(b, "\t\t%s: m.%s,\n", f.name, f.name)
+		} else {
+			fmt.Fprintf(b, "\t\t%s: %s.OptFrom(m.%s),\n", f.name, typxName, f.name)
+		}
+	}
+	b.WriteString("\t}\n}\n")
+
+	fmt.Fprintf(b, "\n// Apply copies the set fields of dto into m.\nfunc (dto %s) Apply(m *%s) {\n", dto, m.name)
+	for _, f := range fields {
+		if f.isOpt {
+			fmt.Fprintf(b, "\tif dto.%s.Set {\n\t\tm.%s = dto.%s\n\t}\n", f.name, f.name, f.name)
+		} else {
+			fmt.Fprintf(b, "\tif dto.%s.Set {\n\t\tm.%s = dto.%s.Val\n\t}\n", f.name, f.name, f.name)
+		}
+	}
+	b.WriteString("}\n")
+
+	fmt.Fprintf(b, `
+// Validate validates dto by calling its validate method, which can be defined on either receiver
+// in another file of the package.
+func (dto %s) Validate() error {
+	if v, ok := any(&dto).(interface{ validate() error }); ok {
+		return v.validate()
+	}
+	return nil
+}
+`, dto)
+
+	writeJSON(b, dto, fields, typxName)
+}
+
+// writeJSON writes the MarshalJSON and UnmarshalJSON methods of dto, which encode its fields as plain JSON values
+// and tell absent members apart from present ones (including null) without relying on encoding/json/v2.
+func writeJSON(b *bytes.Buffer, dto string, fields []field, typxName string) {
+	var jsonFields []field
+	for _, f := range fields {
+		if !f.jsonSkip {
+			jsonFields = append(jsonFields, f)
+		}
+	}
+
+	fmt.Fprintf(b, "\n// MarshalJSON implements the json.Marshaler interface, encoding the values of the set fields only.\n")
+	fmt.Fprintf(b, "func (dto %s) MarshalJSON() ([]byte, error) {\n\tvar out struct {\n", dto)
+	for _, f := range jsonFields {
+		fmt.Fprintf(b, "\t\t%s *%s `json:%q`\n", f.name, f.val, f.jsonName+",omitempty")
+	}
+	b.WriteString("\t}\n")
+	for _, f := range jsonFields {
+		fmt.Fprintf(b, "\tif dto.%s.Set {\n\t\tout.%s = &dto.%s.Val\n\t}\n", f.name, f.name, f.name)
+	}
+	b.WriteString("\treturn json.Marshal(out)\n}\n")
+
+	fmt.Fprintf(b, "\n// UnmarshalJSON implements the json.Unmarshaler interface, setting the fields whose members are present, even if null.\n")
+	fmt.Fprintf(b, "func (dto *%s) UnmarshalJSON(data []byte) error {\n\tvar in struct {\n", dto)
+	for _, f := range jsonFields {
+		if f.jsonName == "" {
+			fmt.Fprintf(b, "\t\t%s json.RawMessage\n", f.name)
+		} else {
+			fmt.Fprintf(b, "\t\t%s json.RawMessage `json:%q`\n", f.name, f.jsonName)
+		}
+	}
+	b.WriteString("\t}\n\tif err := json.Unmarshal(data, &in); err != nil {\n\t\treturn err\n\t}\n")
+	for _, f := range jsonFields {
+		fmt.Fprintf(b, "\tif in.%s != nil {\n\t\tdto.%s = %s.Opt[%s]{Set: true}\n", f.name, f.name, typxName, f.val)
+		fmt.Fprintf(b, "\t\tif err := json.Unmarshal(in.%s, &dto.%s.Val); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n", f.name, f.name)
+	}
+	b.WriteString("\treturn nil\n}\n")
+}
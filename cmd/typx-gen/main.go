@@ -0,0 +1,67 @@
+// Command typx-gen generates Create and Update DTOs from model structs, so that they cannot drift from the models.
+//
+// Usage:
+//
+//	typx-gen -type User[,Order] [-output file] [dir]
+//
+// It is typically invoked with a go:generate directive next to the model:
+//
+//	//go:generate typx-gen -type User
+//
+// For a model User in dir (default "."), it writes user_dto.go containing CreateUserDTO and UpdateUserDTO,
+// where every exported field of type T becomes Opt[T] (and thus Nil[T] becomes Opt[Nil[T]]), along with:
+//   - an Apply(*User) method that copies the set fields into the model without reflection,
+//   - a Validate() method that calls the validate() method of the DTO if it is defined in another file,
+//     on either receiver,
+//   - CreateUserDTOFrom and UpdateUserDTOFrom functions that convert a model back into a full DTO,
+//   - MarshalJSON and UnmarshalJSON methods that encode the set fields as plain JSON values, such as {"name":"bob"},
+//     and set the fields whose members are present, even if null, with encoding/json alone
+//     (which encodes a bare Opt as {"val":...,"set":...} unless encoding/json/v2 is used).
+//
+// Fields are controlled with the typx struct tag: `typx:"-"` excludes a field from both DTOs,
+// and `typx:"readonly"` excludes it from the Update DTO only, so that it is still accepted by the Create DTO
+// and can be set on creation (use `typx:"-"` for fields that clients must never set).
+// Fields tagged `json:"-"` are excluded from both DTOs too, unless they are tagged `typx:"include"`.
+// The json tag name of a field is kept, with the omitzero option added.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated model struct names (required)")
+	output := flag.String("output", "", "output file name (default <type>_dto.go in dir)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: typx-gen -type User [-output file] [dir]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *typeNames == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+
+	src, err := generate(dir, types)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "typx-gen:", err)
+		os.Exit(1)
+	}
+	name := *output
+	if name == "" {
+		name = filepath.Join(dir, strings.ToLower(types[0])+"_dto.go")
+	}
+	if err := os.WriteFile(name, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "typx-gen:", err)
+		os.Exit(1)
+	}
+}
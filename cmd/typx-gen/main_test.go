@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	usermodel "github.com/pedramktb/go-typx/cmd/typx-gen/testdata/src/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Generate(t *testing.T) {
+	const dir = "testdata/src/model"
+	src, err := generate(dir, []string{"User"})
+	require.NoError(t, err)
+	golden, err := os.ReadFile(filepath.Join(dir, "user_dto.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), string(src))
+
+	out, err := exec.Command("go", "vet", "./"+dir).CombinedOutput()
+	assert.NoError(t, err, string(out))
+}
+
+func Test_Generate_JSON(t *testing.T) {
+	var create usermodel.CreateUserDTO
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"bob","landline":null,"email":"bob@example.com"}`), &create))
+	assert.Equal(t, usermodel.CreateUserDTO{
+		Name:     typx.OptFrom("bob"),
+		Landline: typx.OptFrom(typx.Nil[string]{}),
+		Email:    typx.OptFrom("bob@example.com"),
+	}, create)
+	data, err := json.Marshal(create)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"bob","landline":null,"email":"bob@example.com"}`, string(data))
+
+	var update usermodel.UpdateUserDTO
+	require.NoError(t, json.Unmarshal([]byte(`{"mobile":"","Notes":"x"}`), &update))
+	assert.Equal(t, usermodel.UpdateUserDTO{Mobile: typx.OptFrom("")}, update)
+	data, err = json.Marshal(update)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"mobile":""}`, string(data))
+	assert.Error(t, json.Unmarshal([]byte(`{"name":1}`), &update))
+}
+
+func Test_Generate_Validate(t *testing.T) {
+	assert.Error(t, usermodel.CreateUserDTO{}.Validate())
+	assert.NoError(t, usermodel.CreateUserDTO{Name: typx.OptFrom("bob")}.Validate())
+	assert.NoError(t, usermodel.UpdateUserDTO{}.Validate())
+	assert.Error(t, usermodel.UpdateUserDTO{Name: typx.OptFrom("")}.Validate()) // validate has a pointer receiver
+}
+
+func Test_Generate_Errors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "model.go"), []byte(`package model
+
+type Status string
+
+type Page[T any] struct {
+	Items []T
+}
+
+type Embedded struct {
+	Status
+}
+`), 0o644))
+
+	for _, name := range []string{"Missing", "Status", "Page", "Embedded"} {
+		_, err := generate(dir, []string{name})
+		assert.Error(t, err, name)
+	}
+	_, err := generate(filepath.Join(dir, "missing"), []string{"User"})
+	assert.Error(t, err)
+}
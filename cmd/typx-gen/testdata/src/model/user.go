@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pedramktb/go-typx"
+)
+
+//go:generate typx-gen -type User
+
+// User is the main model.
+type User struct {
+	ID             uuid.UUID           `json:"id" typx:"-"`
+	Name           string              `json:"name"`
+	Mobile         string              `json:"mobile,omitempty"`
+	Landline       typx.Nil[string]    `json:"landline"`
+	AdditionalInfo typx.Dyn            `json:"additionalInfo"`
+	Email          typx.Opt[string]    `json:"email,omitzero"`
+	Tags, Roles    []string            `json:"-"`
+	Notes          string              `json:"-" typx:"include"`
+	CreatedAt      time.Time           `json:"createdAt" typx:"readonly"`
+	Settings       map[string]typx.Dyn `json:"settings"`
+	password       string
+}
@@ -0,0 +1,307 @@
+// Code generated by typx-gen; DO NOT EDIT.
+
+package model
+
+import (
+	"encoding/json"
+	"github.com/pedramktb/go-typx"
+	"time"
+)
+
+// CreateUserDTO is the DTO for creating User models, where unset fields are left untouched.
+type CreateUserDTO struct {
+	Name           typx.Opt[string]              `json:"name,omitzero"`
+	Mobile         typx.Opt[string]              `json:"mobile,omitzero"`
+	Landline       typx.Opt[typx.Nil[string]]    `json:"landline,omitzero"`
+	AdditionalInfo typx.Opt[typx.Dyn]            `json:"additionalInfo,omitzero"`
+	Email          typx.Opt[string]              `json:"email,omitzero"`
+	Notes          typx.Opt[string]              `json:"-"`
+	CreatedAt      typx.Opt[time.Time]           `json:"createdAt,omitzero"`
+	Settings       typx.Opt[map[string]typx.Dyn] `json:"settings,omitzero"`
+}
+
+// CreateUserDTOFrom returns the CreateUserDTO of m with all fields set.
+func CreateUserDTOFrom(m User) CreateUserDTO {
+	return CreateUserDTO{
+		Name:           typx.OptFrom(m.Name),
+		Mobile:         typx.OptFrom(m.Mobile),
+		Landline:       typx.OptFrom(m.Landline),
+		AdditionalInfo: typx.OptFrom(m.AdditionalInfo),
+		Email:          m.Email,
+		Notes:          typx.OptFrom(m.Notes),
+		CreatedAt:      typx.OptFrom(m.CreatedAt),
+		Settings:       typx.OptFrom(m.Settings),
+	}
+}
+
+// Apply copies the set fields of dto into m.
+func (dto CreateUserDTO) Apply(m *User) {
+	if dto.Name.Set {
+		m.Name = dto.Name.Val
+	}
+	if dto.Mobile.Set {
+		m.Mobile = dto.Mobile.Val
+	}
+	if dto.Landline.Set {
+		m.Landline = dto.Landline.Val
+	}
+	if dto.AdditionalInfo.Set {
+		m.AdditionalInfo = dto.AdditionalInfo.Val
+	}
+	if dto.Email.Set {
+		m.Email = dto.Email
+	}
+	if dto.Notes.Set {
+		m.Notes = dto.Notes.Val
+	}
+	if dto.CreatedAt.Set {
+		m.CreatedAt = dto.CreatedAt.Val
+	}
+	if dto.Settings.Set {
+		m.Settings = dto.Settings.Val
+	}
+}
+
+// Validate validates dto by calling its validate method, which can be defined on either receiver
+// in another file of the package.
+func (dto CreateUserDTO) Validate() error {
+	if v, ok := any(&dto).(interface{ validate() error }); ok {
+		return v.validate()
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the values of the set fields only.
+func (dto CreateUserDTO) MarshalJSON() ([]byte, error) {
+	var out struct {
+		Name           *string              `json:"name,omitempty"`
+		Mobile         *string              `json:"mobile,omitempty"`
+		Landline       *typx.Nil[string]    `json:"landline,omitempty"`
+		AdditionalInfo *typx.Dyn            `json:"additionalInfo,omitempty"`
+		Email          *string              `json:"email,omitempty"`
+		CreatedAt      *time.Time           `json:"createdAt,omitempty"`
+		Settings       *map[string]typx.Dyn `json:"settings,omitempty"`
+	}
+	if dto.Name.Set {
+		out.Name = &dto.Name.Val
+	}
+	if dto.Mobile.Set {
+		out.Mobile = &dto.Mobile.Val
+	}
+	if dto.Landline.Set {
+		out.Landline = &dto.Landline.Val
+	}
+	if dto.AdditionalInfo.Set {
+		out.AdditionalInfo = &dto.AdditionalInfo.Val
+	}
+	if dto.Email.Set {
+		out.Email = &dto.Email.Val
+	}
+	if dto.CreatedAt.Set {
+		out.CreatedAt = &dto.CreatedAt.Val
+	}
+	if dto.Settings.Set {
+		out.Settings = &dto.Settings.Val
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, setting the fields whose members are present, even if null.
+func (dto *CreateUserDTO) UnmarshalJSON(data []byte) error {
+	var in struct {
+		Name           json.RawMessage `json:"name"`
+		Mobile         json.RawMessage `json:"mobile"`
+		Landline       json.RawMessage `json:"landline"`
+		AdditionalInfo json.RawMessage `json:"additionalInfo"`
+		Email          json.RawMessage `json:"email"`
+		CreatedAt      json.RawMessage `json:"createdAt"`
+		Settings       json.RawMessage `json:"settings"`
+	}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in.Name != nil {
+		dto.Name = typx.Opt[string]{Set: true}
+		if err := json.Unmarshal(in.Name, &dto.Name.Val); err != nil {
+			return err
+		}
+	}
+	if in.Mobile != nil {
+		dto.Mobile = typx.Opt[string]{Set: true}
+		if err := json.Unmarshal(in.Mobile, &dto.Mobile.Val); err != nil {
+			return err
+		}
+	}
+	if in.Landline != nil {
+		dto.Landline = typx.Opt[typx.Nil[string]]{Set: true}
+		if err := json.Unmarshal(in.Landline, &dto.Landline.Val); err != nil {
+			return err
+		}
+	}
+	if in.AdditionalInfo != nil {
+		dto.AdditionalInfo = typx.Opt[typx.Dyn]{Set: true}
+		if err := json.Unmarshal(in.AdditionalInfo, &dto.AdditionalInfo.Val); err != nil {
+			return err
+		}
+	}
+	if in.Email != nil {
+		dto.Email = typx.Opt[string]{Set: true}
+		if err := json.Unmarshal(in.Email, &dto.Email.Val); err != nil {
+			return err
+		}
+	}
+	if in.CreatedAt != nil {
+		dto.CreatedAt = typx.Opt[time.Time]{Set: true}
+		if err := json.Unmarshal(in.CreatedAt, &dto.CreatedAt.Val); err != nil {
+			return err
+		}
+	}
+	if in.Settings != nil {
+		dto.Settings = typx.Opt[map[string]typx.Dyn]{Set: true}
+		if err := json.Unmarshal(in.Settings, &dto.Settings.Val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateUserDTO is the DTO for updating User models, where unset fields are left untouched.
+type UpdateUserDTO struct {
+	Name           typx.Opt[string]              `json:"name,omitzero"`
+	Mobile         typx.Opt[string]              `json:"mobile,omitzero"`
+	Landline       typx.Opt[typx.Nil[string]]    `json:"landline,omitzero"`
+	AdditionalInfo typx.Opt[typx.Dyn]            `json:"additionalInfo,omitzero"`
+	Email          typx.Opt[string]              `json:"email,omitzero"`
+	Notes          typx.Opt[string]              `json:"-"`
+	Settings       typx.Opt[map[string]typx.Dyn] `json:"settings,omitzero"`
+}
+
+// UpdateUserDTOFrom returns the UpdateUserDTO of m with all fields set.
+func UpdateUserDTOFrom(m User) UpdateUserDTO {
+	return UpdateUserDTO{
+		Name:           typx.OptFrom(m.Name),
+		Mobile:         typx.OptFrom(m.Mobile),
+		Landline:       typx.OptFrom(m.Landline),
+		AdditionalInfo: typx.OptFrom(m.AdditionalInfo),
+		Email:          m.Email,
+		Notes:          typx.OptFrom(m.Notes),
+		Settings:       typx.OptFrom(m.Settings),
+	}
+}
+
+// Apply copies the set fields of dto into m.
+func (dto UpdateUserDTO) Apply(m *User) {
+	if dto.Name.Set {
+		m.Name = dto.Name.Val
+	}
+	if dto.Mobile.Set {
+		m.Mobile = dto.Mobile.Val
+	}
+	if dto.Landline.Set {
+		m.Landline = dto.Landline.Val
+	}
+	if dto.AdditionalInfo.Set {
+		m.AdditionalInfo = dto.AdditionalInfo.Val
+	}
+	if dto.Email.Set {
+		m.Email = dto.Email
+	}
+	if dto.Notes.Set {
+		m.Notes = dto.Notes.Val
+	}
+	if dto.Settings.Set {
+		m.Settings = dto.Settings.Val
+	}
+}
+
+// Validate validates dto by calling its validate method, which can be defined on either receiver
+// in another file of the package.
+func (dto UpdateUserDTO) Validate() error {
+	if v, ok := any(&dto).(interface{ validate() error }); ok {
+		return v.validate()
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the values of the set fields only.
+func (dto UpdateUserDTO) MarshalJSON() ([]byte, error) {
+	var out struct {
+		Name           *string              `json:"name,omitempty"`
+		Mobile         *string              `json:"mobile,omitempty"`
+		Landline       *typx.Nil[string]    `json:"landline,omitempty"`
+		AdditionalInfo *typx.Dyn            `json:"additionalInfo,omitempty"`
+		Email          *string              `json:"email,omitempty"`
+		Settings       *map[string]typx.Dyn `json:"settings,omitempty"`
+	}
+	if dto.Name.Set {
+		out.Name = &dto.Name.Val
+	}
+	if dto.Mobile.Set {
+		out.Mobile = &dto.Mobile.Val
+	}
+	if dto.Landline.Set {
+		out.Landline = &dto.Landline.Val
+	}
+	if dto.AdditionalInfo.Set {
+		out.AdditionalInfo = &dto.AdditionalInfo.Val
+	}
+	if dto.Email.Set {
+		out.Email = &dto.Email.Val
+	}
+	if dto.Settings.Set {
+		out.Settings = &dto.Settings.Val
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, setting the fields whose members are present, even if null.
+func (dto *UpdateUserDTO) UnmarshalJSON(data []byte) error {
+	var in struct {
+		Name           json.RawMessage `json:"name"`
+		Mobile         json.RawMessage `json:"mobile"`
+		Landline       json.RawMessage `json:"landline"`
+		AdditionalInfo json.RawMessage `json:"additionalInfo"`
+		Email          json.RawMessage `json:"email"`
+		Settings       json.RawMessage `json:"settings"`
+	}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in.Name != nil {
+		dto.Name = typx.Opt[string]{Set: true}
+		if err := json.Unmarshal(in.Name, &dto.Name.Val); err != nil {
+			return err
+		}
+	}
+	if in.Mobile != nil {
+		dto.Mobile = typx.Opt[string]{Set: true}
+		if err := json.Unmarshal(in.Mobile, &dto.Mobile.Val); err != nil {
+			return err
+		}
+	}
+	if in.Landline != nil {
+		dto.Landline = typx.Opt[typx.Nil[string]]{Set: true}
+		if err := json.Unmarshal(in.Landline, &dto.Landline.Val); err != nil {
+			return err
+		}
+	}
+	if in.AdditionalInfo != nil {
+		dto.AdditionalInfo = typx.Opt[typx.Dyn]{Set: true}
+		if err := json.Unmarshal(in.AdditionalInfo, &dto.AdditionalInfo.Val); err != nil {
+			return err
+		}
+	}
+	if in.Email != nil {
+		dto.Email = typx.Opt[string]{Set: true}
+		if err := json.Unmarshal(in.Email, &dto.Email.Val); err != nil {
+			return err
+		}
+	}
+	if in.Settings != nil {
+		dto.Settings = typx.Opt[map[string]typx.Dyn]{Set: true}
+		if err := json.Unmarshal(in.Settings, &dto.Settings.Val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
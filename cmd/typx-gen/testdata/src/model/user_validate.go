@@ -0,0 +1,17 @@
+package model
+
+import "errors"
+
+func (dto CreateUserDTO) validate() error {
+	if !dto.Name.Set || dto.Name.Val == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+func (dto *UpdateUserDTO) validate() error {
+	if dto.Name.Set && dto.Name.Val == "" {
+		return errors.New("name cannot be empty")
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// listedPackage is the subset of the output of go list used to load packages.
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	GoFiles    []string
+	Export     string
+	DepOnly    bool
+	ImportMap  map[string]string
+	Error      *struct{ Err string }
+}
+
+// loadedPackage is a parsed and type-checked package along with the sources of its files.
+type loadedPackage struct {
+	files   []*ast.File
+	sources map[*ast.File][]byte
+	pkg     *types.Package
+	info    *types.Info
+}
+
+// load parses and type-checks the packages matching patterns.
+// Dependencies are imported from the export data produced by go list -export.
+func load(fset *token.FileSet, patterns []string) ([]*loadedPackage, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	args := append([]string{"list", "-e", "-export", "-deps", "-json=ImportPath,Dir,GoFiles,Export,DepOnly,ImportMap,Error"}, patterns...)
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %w: %s", err, stderr.String())
+	}
+
+	var listed []listedPackage
+	exports := map[string]string{}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for dec.More() {
+		var pkg listedPackage
+		if err := dec.Decode(&pkg); err != nil {
+			return nil, fmt.Errorf("go list: %w", err)
+		}
+		if pkg.Export != "" {
+			exports[pkg.ImportPath] = pkg.Export
+		}
+		if !pkg.DepOnly {
+			listed = append(listed, pkg)
+		}
+	}
+	if len(listed) == 0 {
+		return nil, errors.New("no packages matched")
+	}
+
+	gc := importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		export, ok := exports[path]
+		if !ok {
+			return nil, fmt.Errorf("no export data for %q", path)
+		}
+		return os.Open(export)
+	})
+	var pkgs []*loadedPackage
+	for _, pkg := range listed {
+		if pkg.Error != nil {
+			return nil, fmt.Errorf("%s: %s", pkg.ImportPath, pkg.Error.Err)
+		}
+		loaded := &loadedPackage{
+			sources: map[*ast.File][]byte{},
+			info: &types.Info{
+				Types:      map[ast.Expr]types.TypeAndValue{},
+				Defs:       map[*ast.Ident]types.Object{},
+				Uses:       map[*ast.Ident]types.Object{},
+				Implicits:  map[ast.Node]types.Object{},
+				Selections: map[*ast.SelectorExpr]*types.Selection{},
+			},
+		}
+		for _, name := range pkg.GoFiles {
+			src, err := os.ReadFile(filepath.Join(pkg.Dir, name))
+			if err != nil {
+				return nil, err
+			}
+			file, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), src, parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+			loaded.files = append(loaded.files, file)
+			loaded.sources[file] = src
+		}
+		conf := types.Config{Importer: importerFunc(func(path string) (*types.Package, error) {
+			if mapped, ok := pkg.ImportMap[path]; ok {
+				path = mapped
+			}
+			return gc.Import(path)
+		})}
+		loaded.pkg, err = conf.Check(pkg.ImportPath, fset, loaded.files, loaded.info)
+		if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, loaded)
+	}
+	return pkgs, nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
@@ -0,0 +1,73 @@
+// Command typx-migrate rewrites struct fields of type *T, sql.NullString and the like, and sql.Null[T] into Nil[T],
+// and map[string]any JSON columns into Dyn, along with the common uses of these fields.
+//
+// Usage:
+//
+//	typx-migrate [-w] [-types User,Order] [packages]
+//
+// Packages are given as for go list and default to "./...". Pointer and map fields are only migrated if they
+// have a struct tag, since untagged ones are usually references rather than data. Untagged pointer and map fields
+// and pointers to types that would contain the struct itself (such as Next *Node) are listed and left as is.
+// The uses that are rewritten are:
+//   - x.F != nil and x.F == nil, which become x.F.NotNil and !x.F.NotNil (x.F.Val != nil for a Dyn)
+//   - *x.F, which becomes x.F.Val
+//   - x.F.Valid and x.F.String (or x.F.V and the like), which become x.F.NotNil and x.F.Val
+//   - &v, typx.Ptr(v) and nil assigned to x.F or used for F in a literal, which become typx.NilFrom(v) and typx.Nil[T]{}
+//   - sql.NullString{String: s, Valid: true} assigned to x.F, which becomes typx.NilFrom(s)
+//   - m assigned to a map field, which becomes typx.Dyn{Val: m}
+//   - &x.F passed as any or as an interface of decoding methods, as to rows.Scan or json.Unmarshal, which is left as is
+//
+// It prints a unified diff of the changes, or writes them to the files with -w, and lists the fields above and the
+// other uses of migrated fields, which are left as is for a manual rewrite. It exits with status 1 if any are listed.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	write := flag.Bool("w", false, "write the changes to the files instead of printing a diff")
+	typeNames := flag.String("types", "", "comma separated struct types to migrate (default all)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: typx-migrate [flags] [packages]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	var types []string
+	if *typeNames != "" {
+		types = strings.Split(*typeNames, ",")
+	}
+
+	fset := token.NewFileSet()
+	results, sites, err := migrate(fset, flag.Args(), types)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "typx-migrate:", err)
+		os.Exit(2)
+	}
+	wd, _ := os.Getwd()
+	for _, result := range results {
+		name := result.Filename
+		if rel, err := filepath.Rel(wd, name); err == nil && !strings.HasPrefix(rel, "..") {
+			name = filepath.ToSlash(rel)
+		}
+		if *write {
+			if err := os.WriteFile(result.Filename, result.New, 0o644); err != nil {
+				fmt.Fprintln(os.Stderr, "typx-migrate:", err)
+				os.Exit(2)
+			}
+			continue
+		}
+		os.Stdout.Write(unifiedDiff("a/"+name, "b/"+name, result.Old, result.New))
+	}
+	for _, site := range sites {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", fset.Position(site.Pos), site.Message)
+	}
+	if len(sites) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Migrate(t *testing.T) {
+	fset := token.NewFileSet()
+	results, sites, err := migrate(fset, []string{"./testdata/src/models"}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	for _, result := range results {
+		golden, err := os.ReadFile(filepath.Join("testdata/golden", filepath.Base(result.Filename)+".golden"))
+		require.NoError(t, err)
+		assert.Equal(t, string(golden), string(result.New), result.Filename)
+	}
+
+	got := map[string]int{}
+	for _, site := range sites {
+		pos := fset.Position(site.Pos)
+		assert.NotEmpty(t, site.Message)
+		got[fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line)]++
+	}
+	want := map[string]int{}
+	for _, name := range []string{"models.go", "use.go"} {
+		file, err := os.Open(filepath.Join("testdata/src/models", name))
+		require.NoError(t, err)
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for line := 1; scanner.Scan(); line++ {
+			if _, comment, ok := strings.Cut(scanner.Text(), "// unsafe"); ok {
+				want[fmt.Sprintf("%s:%d", name, line)] = 1 + strings.Count(comment, "unsafe")
+			}
+		}
+	}
+	assert.Equal(t, want, got)
+}
+
+func Test_Migrate_Types(t *testing.T) {
+	results, sites, err := migrate(token.NewFileSet(), []string{"./testdata/src/models"}, []string{"Address"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Empty(t, sites)
+}
+
+func Test_Migrate_NoPackages(t *testing.T) {
+	_, _, err := migrate(token.NewFileSet(), []string{"./testdata/src/missing"}, nil)
+	assert.Error(t, err)
+}
+
+func Test_UnifiedDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	new := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+	assert.Equal(t, `--- a/x.go
++++ b/x.go
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -10,3 +10,4 @@
+ j
+ k
+ l
++m
+`, string(unifiedDiff("a/x.go", "b/x.go", []byte(old), []byte(new))))
+	assert.Empty(t, unifiedDiff("a/x.go", "b/x.go", []byte(old), []byte(old)))
+	assert.Equal(t, "--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n", string(unifiedDiff("a", "b", nil, []byte("x\n"))))
+}
@@ -0,0 +1,776 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const typxPath = "github.com/pedramktb/go-typx"
+
+// fieldKind is the kind of a migrated struct field.
+type fieldKind int
+
+const (
+	ptrField     fieldKind = iota // *T becomes Nil[T]
+	sqlNullField                  // sql.NullString, sql.Null[T] and the like become Nil[T]
+	mapField                      // map[string]any becomes Dyn
+)
+
+// migratedField is a struct field whose type is migrated.
+type migratedField struct {
+	kind  fieldKind
+	elem  types.Type // the T of Nil[T], nil for Dyn
+	value string     // the value field of a sql null type, e.g. String for sql.NullString
+	name  string     // the new type for messages, e.g. Nil[string]
+}
+
+// Site is a field or a use of a migrated field that could not be migrated safely and is left as is.
+type Site struct {
+	Pos     token.Pos
+	Message string
+}
+
+// Result is a rewritten file.
+type Result struct {
+	Filename string
+	Old, New []byte
+}
+
+// migrator rewrites the migrated fields of the loaded packages and their uses.
+type migrator struct {
+	types  []string // the struct types whose fields are migrated, all if empty
+	fields map[string]*migratedField
+	sites  []Site
+}
+
+// fileEdit collects the edits of a file.
+type fileEdit struct {
+	pkg     *loadedPackage
+	file    *ast.File
+	src     []byte
+	edits   []edit
+	imports map[string]string // import path -> name
+	added   []string          // import paths to add
+}
+
+// edit replaces the bytes from start to end of a file with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// migrate rewrites the fields of the struct types named in typeNames (all if empty) in the packages
+// matching patterns, along with the uses of these fields in the same packages.
+func migrate(fset *token.FileSet, patterns, typeNames []string) ([]Result, []Site, error) {
+	pkgs, err := load(fset, patterns)
+	if err != nil {
+		return nil, nil, err
+	}
+	m := &migrator{types: typeNames, fields: map[string]*migratedField{}}
+	var files []*fileEdit
+	for _, pkg := range pkgs {
+		for _, file := range pkg.files {
+			fe := &fileEdit{pkg: pkg, file: file, src: pkg.sources[file], imports: map[string]string{}}
+			for _, spec := range file.Imports {
+				path, _ := strconv.Unquote(spec.Path.Value)
+				if spec.Name != nil {
+					fe.imports[path] = spec.Name.Name
+				} else if obj, ok := pkg.info.Implicits[spec].(*types.PkgName); ok {
+					fe.imports[path] = obj.Imported().Name()
+				} else {
+					fe.imports[path] = path[strings.LastIndex(path, "/")+1:]
+				}
+			}
+			m.collect(fe)
+			files = append(files, fe)
+		}
+	}
+	var results []Result
+	for _, fe := range files {
+		m.rewrite(fe)
+		if len(fe.edits) == 0 {
+			continue
+		}
+		src, err := fe.apply()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", fset.Position(fe.file.Pos()).Filename, err)
+		}
+		results = append(results, Result{Filename: fset.Position(fe.file.Pos()).Filename, Old: fe.src, New: src})
+	}
+	sort.Slice(m.sites, func(i, j int) bool { return m.sites[i].Pos < m.sites[j].Pos })
+	return results, m.sites, nil
+}
+
+// collect finds the migrated fields declared in fe and rewrites their types.
+func (m *migrator) collect(fe *fileEdit) {
+	for _, decl := range fe.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || (len(m.types) > 0 && !slices.Contains(m.types, ts.Name.Name)) {
+				continue
+			}
+			named, ok := fe.pkg.info.Defs[ts.Name].Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				t := fe.pkg.info.TypeOf(field.Type)
+				f := classify(t, field.Tag != nil, fe.pkg.pkg)
+				if f == nil && field.Tag == nil {
+					// Report the untagged pointers and maps that would be migrated if they were tagged.
+					if f := classify(t, true, fe.pkg.pkg); f != nil {
+						what := "pointer"
+						if f.kind == mapField {
+							what = "map"
+						}
+						for _, name := range field.Names {
+							m.report(name.Pos(), "%s.%s is left as is, since it is an untagged %s field", ts.Name.Name, name.Name, what)
+						}
+					}
+				}
+				if f == nil || len(field.Names) == 0 {
+					continue
+				}
+				if f.kind == ptrField && m.contains(f.elem, named, map[*types.Named]bool{}) {
+					for _, name := range field.Names {
+						m.report(name.Pos(), "%s.%s is left as is, since %s would be an invalid recursive type", ts.Name.Name, name.Name, f.name)
+					}
+					continue
+				}
+				for _, name := range field.Names {
+					m.fields[fieldKey(named, name.Name)] = f
+				}
+				typ := fe.typxName() + ".Dyn"
+				if f.kind != mapField {
+					elem := fe.typeString(f.elem)
+					switch expr := field.Type.(type) {
+					case *ast.StarExpr:
+						elem = fe.text(expr.X)
+					case *ast.IndexExpr:
+						elem = fe.text(expr.Index)
+					}
+					typ = fe.typxName() + ".Nil[" + elem + "]"
+				}
+				fe.replace(field.Type, typ)
+			}
+		}
+	}
+}
+
+// classify returns how a field of type t is migrated, or nil if it is not.
+// Pointers and maps are only migrated if the field is tagged, since untagged ones are usually references rather than data.
+func classify(t types.Type, tagged bool, pkg *types.Package) *migratedField {
+	qualifier := types.RelativeTo(pkg)
+	switch u := types.Unalias(t).(type) {
+	case *types.Pointer:
+		if !tagged || isTypx(u.Elem()) {
+			return nil
+		}
+		switch elem := u.Elem().Underlying().(type) {
+		case *types.Interface, *types.Pointer, *types.Signature, *types.Chan:
+			return nil
+		case *types.Basic:
+			if elem.Kind() == types.UnsafePointer {
+				return nil
+			}
+		}
+		return &migratedField{kind: ptrField, elem: u.Elem(), name: "Nil[" + types.TypeString(u.Elem(), qualifier) + "]"}
+	case *types.Named:
+		obj := u.Obj()
+		st, ok := u.Underlying().(*types.Struct)
+		if obj.Pkg() == nil || obj.Pkg().Path() != "database/sql" || !strings.HasPrefix(obj.Name(), "Null") ||
+			!ok || st.NumFields() != 2 || st.Field(1).Name() != "Valid" {
+			return nil
+		}
+		value := st.Field(0)
+		return &migratedField{kind: sqlNullField, elem: value.Type(), value: value.Name(), name: "Nil[" + types.TypeString(value.Type(), qualifier) + "]"}
+	case *types.Map:
+		key, ok := u.Key().Underlying().(*types.Basic)
+		elem, isInterface := u.Elem().Underlying().(*types.Interface)
+		if !tagged || !ok || key.Kind() != types.String || !isInterface || !elem.Empty() {
+			return nil
+		}
+		return &migratedField{kind: mapField, name: "Dyn"}
+	}
+	return nil
+}
+
+// contains reports whether a value of type t contains a value of type target once migrated,
+// following struct fields and array elements as well as the pointer fields that become Nil[T].
+func (m *migrator) contains(t types.Type, target *types.Named, seen map[*types.Named]bool) bool {
+	switch u := types.Unalias(t).(type) {
+	case *types.Named:
+		if types.Identical(u, target) {
+			return true
+		}
+		if seen[u] {
+			return false
+		}
+		seen[u] = true
+		st, ok := u.Underlying().(*types.Struct)
+		if !ok {
+			return m.contains(u.Underlying(), target, seen)
+		}
+		migrated := len(m.types) == 0 || slices.Contains(m.types, u.Obj().Name())
+		for i := range st.NumFields() {
+			ft := st.Field(i).Type()
+			if f := classify(ft, st.Tag(i) != "", u.Obj().Pkg()); migrated && f != nil && f.kind == ptrField {
+				ft = f.elem
+			}
+			if m.contains(ft, target, seen) {
+				return true
+			}
+		}
+	case *types.Struct:
+		for i := range u.NumFields() {
+			if m.contains(u.Field(i).Type(), target, seen) {
+				return true
+			}
+		}
+	case *types.Array:
+		return m.contains(u.Elem(), target, seen)
+	}
+	return false
+}
+
+func isTypx(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == typxPath
+}
+
+// fieldKey identifies a field of a named struct type across packages.
+func fieldKey(named *types.Named, field string) string {
+	obj := named.Origin().Obj()
+	return obj.Pkg().Path() + "." + obj.Name() + "." + field
+}
+
+// field returns the migrated field selected by expr, if any.
+func (m *migrator) field(info *types.Info, expr ast.Expr) *migratedField {
+	sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	selection := info.Selections[sel]
+	if selection == nil || selection.Kind() != types.FieldVal {
+		return nil
+	}
+	t := selection.Recv()
+	index := selection.Index()
+	for i, j := range index {
+		if ptr, ok := t.Underlying().(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		st, ok := t.Underlying().(*types.Struct)
+		if !ok {
+			return nil
+		}
+		if i == len(index)-1 {
+			named, ok := types.Unalias(t).(*types.Named)
+			if !ok {
+				return nil
+			}
+			return m.fields[fieldKey(named, st.Field(j).Name())]
+		}
+		t = st.Field(j).Type()
+	}
+	return nil
+}
+
+// sameType reports whether a and b have the same type after the migration.
+func sameType(a, b *migratedField) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	if a.kind == mapField || b.kind == mapField {
+		return a.kind == b.kind
+	}
+	return types.Identical(a.elem, b.elem)
+}
+
+// rewrite rewrites the uses of migrated fields in fe.
+func (m *migrator) rewrite(fe *fileEdit) {
+	var stack []ast.Node
+	ast.Inspect(fe.file, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if f := m.field(fe.pkg.info, n); f != nil {
+				m.rewriteUse(fe, f, n, stack[:len(stack)-1])
+			}
+		case *ast.CompositeLit:
+			m.rewriteLit(fe, n)
+		}
+		return true
+	})
+}
+
+func (m *migrator) report(pos token.Pos, format string, args ...any) {
+	m.sites = append(m.sites, Site{Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+// rewriteUse rewrites a use of a migrated field, given the ancestors of the use.
+func (m *migrator) rewriteUse(fe *fileEdit, f *migratedField, sel *ast.SelectorExpr, ancestors []ast.Node) {
+	info := fe.pkg.info
+	parent := ancestors[len(ancestors)-1]
+	switch p := parent.(type) {
+	case *ast.UnaryExpr:
+		// &x.F is left as is when it is passed to a function as any or as a decoding interface,
+		// such as rows.Scan or json.Unmarshal, since *Nil[T] and *Dyn implement the same decoding methods.
+		if call, ok := ancestors[len(ancestors)-2].(*ast.CallExpr); ok && p.Op == token.AND && decodesInto(paramType(info, call, p)) {
+			return
+		}
+	case *ast.AssignStmt:
+		if i := slices.Index(p.Lhs, ast.Expr(sel)); i >= 0 {
+			if p.Tok == token.ASSIGN && len(p.Lhs) == len(p.Rhs) && m.rewriteAssigned(fe, f, p.Rhs[i]) {
+				return
+			}
+		} else if i := slices.Index(p.Rhs, ast.Expr(sel)); i >= 0 && len(p.Lhs) == len(p.Rhs) && sameType(f, m.field(info, p.Lhs[i])) {
+			return
+		}
+	case *ast.KeyValueExpr:
+		if p.Value == ast.Expr(sel) && m.keyField(info, ancestors, p) != nil {
+			return // checked by rewriteLit
+		}
+	case *ast.BinaryExpr:
+		other := p.X
+		if other == ast.Expr(sel) {
+			other = p.Y
+		}
+		if (p.Op == token.EQL || p.Op == token.NEQ) && info.Types[other].IsNil() && f.kind != sqlNullField {
+			// The nil operand is removed, and x.F != nil becomes x.F.NotNil or x.F.Val != nil for a Dyn.
+			if f.kind == mapField {
+				fe.insert(sel.End(), ".Val")
+				return
+			}
+			if p.Op == token.EQL {
+				fe.insert(p.Pos(), "!")
+			}
+			if other == p.X {
+				fe.replaceRange(p.Pos(), sel.Pos(), "")
+			}
+			fe.insert(sel.End(), ".NotNil")
+			if other == p.Y {
+				fe.replaceRange(sel.End(), p.End(), "")
+			}
+			return
+		}
+	case *ast.StarExpr:
+		if f.kind == ptrField {
+			if isWritten(p, ancestors[:len(ancestors)-1]) {
+				m.report(p.Pos(), "%s is written through %s, which becomes %s: assign %s.NilFrom(...) to it instead", fe.text(p), fe.text(sel), f.name, fe.typxName())
+				return
+			}
+			fe.replaceRange(p.Pos(), p.Pos()+1, "")
+			fe.insert(sel.End(), ".Val")
+			return
+		}
+	case *ast.SelectorExpr:
+		if f.kind == sqlNullField && p.X == ast.Expr(sel) {
+			switch p.Sel.Name {
+			case "Valid":
+				fe.replace(p.Sel, "NotNil")
+			case f.value:
+				fe.replace(p.Sel, "Val")
+			}
+			// Other selectors are the Scan and Value methods, which Nil[T] implements as well.
+			return
+		}
+	}
+	m.report(sel.Pos(), "%s becomes %s: rewrite this use manually", fe.text(sel), f.name)
+}
+
+// decodingMethods are the pointer methods that both Nil[T] and Dyn implement for decoding values.
+var decodingMethods = []string{
+	"GobDecode", "Scan", "UnmarshalBSONValue", "UnmarshalBinary", "UnmarshalGQL", "UnmarshalJSON",
+	"UnmarshalJSONFrom", "UnmarshalMsgpack", "UnmarshalText", "UnmarshalXML", "UnmarshalXMLAttr", "UnmarshalYAML",
+}
+
+// decodesInto reports whether a pointer to a migrated field can still be passed as a parameter of type t,
+// which is the case if t is any or an interface of decoding methods, such as sql.Scanner or json.Unmarshaler.
+func decodesInto(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	iface, ok := t.Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
+	for method := range iface.Methods() {
+		if !slices.Contains(decodingMethods, method.Name()) {
+			return false
+		}
+	}
+	return true
+}
+
+// paramType returns the type of the parameter that arg is passed as in call, or nil if it is unknown.
+func paramType(info *types.Info, call *ast.CallExpr, arg ast.Expr) types.Type {
+	tv, ok := info.Types[call.Fun]
+	if !ok || tv.IsType() {
+		return nil
+	}
+	sig, ok := tv.Type.Underlying().(*types.Signature)
+	i := slices.Index(call.Args, arg)
+	if !ok || i < 0 || sig.Params().Len() == 0 {
+		return nil
+	}
+	params := sig.Params()
+	if sig.Variadic() && i >= params.Len()-1 {
+		last := params.At(params.Len() - 1).Type()
+		if slice, ok := last.Underlying().(*types.Slice); ok && !call.Ellipsis.IsValid() {
+			return slice.Elem()
+		}
+		return last
+	}
+	if i >= params.Len() {
+		return nil
+	}
+	return params.At(i).Type()
+}
+
+// isWritten reports whether expr is assigned to, incremented or addressed, given its ancestors.
+func isWritten(expr ast.Expr, ancestors []ast.Node) bool {
+	switch p := ancestors[len(ancestors)-1].(type) {
+	case *ast.AssignStmt:
+		return slices.Contains(p.Lhs, expr)
+	case *ast.IncDecStmt:
+		return true
+	case *ast.UnaryExpr:
+		return p.Op == token.AND
+	case *ast.RangeStmt:
+		return p.Key == expr || p.Value == expr
+	}
+	return false
+}
+
+// keyField returns the migrated field of the key of kv in a composite literal, given the ancestors of the value of kv.
+func (m *migrator) keyField(info *types.Info, ancestors []ast.Node, kv *ast.KeyValueExpr) *migratedField {
+	lit, ok := ancestors[len(ancestors)-2].(*ast.CompositeLit)
+	key, isIdent := kv.Key.(*ast.Ident)
+	if !ok || !isIdent {
+		return nil
+	}
+	named := literalType(info, lit)
+	if named == nil {
+		return nil
+	}
+	return m.fields[fieldKey(named, key.Name)]
+}
+
+// literalType returns the named struct type of lit, if any.
+func literalType(info *types.Info, lit *ast.CompositeLit) *types.Named {
+	t := info.TypeOf(lit)
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return named
+}
+
+// rewriteLit rewrites the migrated fields in a composite literal.
+func (m *migrator) rewriteLit(fe *fileEdit, lit *ast.CompositeLit) {
+	info := fe.pkg.info
+	named := literalType(info, lit)
+	if named == nil {
+		return
+	}
+	st := named.Underlying().(*types.Struct)
+	for i, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			if f := m.fields[fieldKey(named, st.Field(i).Name())]; f != nil {
+				m.report(elt.Pos(), "%s of unkeyed %s literal becomes %s: rewrite this value manually", st.Field(i).Name(), named.Obj().Name(), f.name)
+			}
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		f := m.fields[fieldKey(named, key.Name)]
+		if f == nil || sameType(f, m.field(info, kv.Value)) {
+			continue
+		}
+		if !m.rewriteAssigned(fe, f, kv.Value) {
+			m.report(kv.Value.Pos(), "%s assigned to %s, which becomes %s: rewrite this value manually", fe.text(kv.Value), key.Name, f.name)
+		}
+	}
+}
+
+// rewriteAssigned rewrites a value assigned to a migrated field, and reports whether it could.
+func (m *migrator) rewriteAssigned(fe *fileEdit, f *migratedField, value ast.Expr) bool {
+	info := fe.pkg.info
+	if sameType(f, m.field(info, value)) {
+		return true
+	}
+	typx := fe.typxName()
+	if info.Types[value].IsNil() {
+		if f.kind == mapField {
+			fe.replace(value, typx+".Dyn{}")
+		} else {
+			fe.replace(value, typx+".Nil["+fe.typeString(f.elem)+"]{}")
+		}
+		return true
+	}
+	switch f.kind {
+	case ptrField:
+		switch v := ast.Unparen(value).(type) {
+		case *ast.UnaryExpr:
+			if v.Op == token.AND {
+				fe.replaceRange(v.Pos(), v.X.Pos(), typx+".NilFrom(")
+				fe.insert(v.End(), ")")
+				return true
+			}
+		case *ast.CallExpr:
+			if fn := callee(info, v); fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == typxPath && fn.Name() == "Ptr" {
+				fe.replaceRange(v.Fun.Pos(), v.Lparen, typx+".NilFrom")
+				return true
+			}
+		}
+	case sqlNullField:
+		lit, ok := ast.Unparen(value).(*ast.CompositeLit)
+		if !ok || lit.Type == nil {
+			return false
+		}
+		var val, valid ast.Expr
+		var keys []*ast.Ident
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				key := kv.Key.(*ast.Ident)
+				switch key.Name {
+				case f.value:
+					val = kv.Value
+				case "Valid":
+					valid = kv.Value
+				}
+				keys = append(keys, key)
+			}
+		}
+		if ident, ok := valid.(*ast.Ident); ok && val != nil && len(lit.Elts) == 2 && info.Uses[ident] == types.Universe.Lookup("true") {
+			// sql.NullString{String: s, Valid: true} becomes typx.NilFrom(s).
+			fe.replaceRange(lit.Pos(), val.Pos(), typx+".NilFrom(")
+			fe.replaceRange(val.End(), lit.End(), ")")
+			return true
+		}
+		for _, key := range keys {
+			if key.Name == "Valid" {
+				fe.replace(key, "NotNil")
+			} else {
+				fe.replace(key, "Val")
+			}
+		}
+		fe.replace(lit.Type, typx+".Nil["+fe.typeString(f.elem)+"]")
+		return true
+	case mapField:
+		fe.insert(value.Pos(), typx+".Dyn{Val: ")
+		fe.insert(value.End(), "}")
+		return true
+	}
+	return false
+}
+
+// callee returns the function called by call, if it is a function rather than a method or a conversion.
+func callee(info *types.Info, call *ast.CallExpr) *types.Func {
+	fun := ast.Unparen(call.Fun)
+	if index, ok := fun.(*ast.IndexExpr); ok {
+		fun = index.X
+	}
+	var ident *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	}
+	if ident == nil {
+		return nil
+	}
+	fn, _ := info.Uses[ident].(*types.Func)
+	if fn == nil || fn.Signature().Recv() != nil {
+		return nil
+	}
+	return fn
+}
+
+func (fe *fileEdit) offset(pos token.Pos) int {
+	return int(pos - fe.file.FileStart)
+}
+
+func (fe *fileEdit) text(node ast.Node) string {
+	return string(fe.src[fe.offset(node.Pos()):fe.offset(node.End())])
+}
+
+func (fe *fileEdit) replace(node ast.Node, text string) {
+	fe.replaceRange(node.Pos(), node.End(), text)
+}
+
+func (fe *fileEdit) replaceRange(start, end token.Pos, text string) {
+	fe.edits = append(fe.edits, edit{start: fe.offset(start), end: fe.offset(end), text: text})
+}
+
+func (fe *fileEdit) insert(pos token.Pos, text string) {
+	fe.replaceRange(pos, pos, text)
+}
+
+// importName returns the name of the package with path in the file, adding an import if it is missing.
+func (fe *fileEdit) importName(path, name string) string {
+	if existing, ok := fe.imports[path]; ok {
+		return existing
+	}
+	fe.imports[path] = name
+	fe.added = append(fe.added, path)
+	return name
+}
+
+func (fe *fileEdit) typxName() string {
+	return fe.importName(typxPath, "typx")
+}
+
+// typeString returns t as written in the file, adding imports for the packages it refers to.
+func (fe *fileEdit) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == fe.pkg.pkg {
+			return ""
+		}
+		return fe.importName(p.Path(), p.Name())
+	})
+}
+
+// apply returns the formatted source of the file with the edits and added imports applied,
+// removing the database/sql import if it is no longer used.
+func (fe *fileEdit) apply() ([]byte, error) {
+	if len(fe.added) > 0 {
+		fe.addImports()
+	}
+	sort.SliceStable(fe.edits, func(i, j int) bool { return fe.edits[i].start < fe.edits[j].start })
+	var b bytes.Buffer
+	last := 0
+	for _, e := range fe.edits {
+		if e.start < last {
+			return nil, fmt.Errorf("overlapping edits at offset %d", e.start)
+		}
+		b.Write(fe.src[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.Write(fe.src[last:])
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return removeUnusedImport(src, "database/sql")
+}
+
+// addImports adds the added imports to the first import declaration of the file, after the standard library ones.
+func (fe *fileEdit) addImports() {
+	sort.SliceStable(fe.added, func(i, j int) bool { return isStd(fe.added[i]) && !isStd(fe.added[j]) })
+	var decl *ast.GenDecl
+	for _, d := range fe.file.Decls {
+		if gen, ok := d.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			decl = gen
+			break
+		}
+	}
+	if decl == nil {
+		fe.insert(fe.file.Name.End(), "\n\nimport ("+fe.importSpecs(nil)+"\n)")
+		return
+	}
+	if !decl.Lparen.IsValid() {
+		fe.insert(decl.Specs[0].Pos(), "(\n")
+		fe.insert(decl.End(), fe.importSpecs(decl.Specs[0].(*ast.ImportSpec))+"\n)")
+		return
+	}
+	fe.insert(decl.Specs[len(decl.Specs)-1].End(), fe.importSpecs(decl.Specs[len(decl.Specs)-1].(*ast.ImportSpec)))
+}
+
+// importSpecs returns the added imports to insert after the import spec last, starting a new group for the
+// first third-party import after a standard library one.
+func (fe *fileEdit) importSpecs(last *ast.ImportSpec) string {
+	var b strings.Builder
+	std := false
+	if last != nil {
+		path, _ := strconv.Unquote(last.Path.Value)
+		std = isStd(path)
+	}
+	for _, path := range fe.added {
+		if std && !isStd(path) {
+			b.WriteString("\n")
+		}
+		std = isStd(path)
+		b.WriteString("\n" + strconv.Quote(path))
+	}
+	return b.String()
+}
+
+func isStd(path string) bool {
+	elem, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(elem, ".")
+}
+
+// removeUnusedImport removes the import of path from src if it is no longer referred to.
+func removeUnusedImport(src []byte, path string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			spec := spec.(*ast.ImportSpec)
+			if spec.Path.Value != strconv.Quote(path) {
+				continue
+			}
+			name := path[strings.LastIndex(path, "/")+1:]
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			used := name == "_" || name == "."
+			ast.Inspect(file, func(n ast.Node) bool {
+				if sel, ok := n.(*ast.SelectorExpr); ok {
+					if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == name && ident.Obj == nil {
+						used = true
+					}
+				}
+				return !used
+			})
+			if used {
+				return src, nil
+			}
+			var node ast.Node = spec
+			if len(gen.Specs) == 1 {
+				node = gen
+			}
+			start, end := fset.Position(node.Pos()).Offset, fset.Position(node.End()).Offset
+			return format.Source(append(src[:start:start], src[end:]...))
+		}
+	}
+	return src, nil
+}
@@ -0,0 +1,20 @@
+package models
+
+import "database/sql"
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type User struct {
+	ID        int64             `json:"id"`
+	Name      *string           `json:"name"`
+	Age       *int              `json:"age"`
+	Address   *Address          `json:"address"`
+	Email     sql.NullString    `json:"email"`
+	DeletedAt sql.NullTime      `json:"deletedAt"`
+	Score     sql.Null[float64] `json:"score"`
+	Settings  map[string]any    `json:"settings"`
+	Parent    *User             // unsafe
+	Cache     map[string]any    // unsafe
+}
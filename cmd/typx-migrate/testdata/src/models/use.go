@@ -0,0 +1,96 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pedramktb/go-typx"
+)
+
+func NewUser(name string, rows *sql.Rows) (*User, error) {
+	u := &User{
+		Name:     &name,
+		Age:      typx.Ptr(30),
+		Address:  nil,
+		Email:    sql.NullString{String: name, Valid: true},
+		Score:    sql.Null[float64]{V: 1, Valid: name != ""},
+		Settings: map[string]any{"theme": "dark"},
+	}
+	if err := rows.Scan(&u.ID, &u.Name, &u.Email); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(`{}`), &u.Settings); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (u *User) String() string {
+	name := "anonymous"
+	if u.Name != nil {
+		name = *u.Name
+	}
+	if nil == u.Age {
+		return name
+	}
+	if u.Email.Valid {
+		name += " <" + u.Email.String + ">"
+	}
+	if u.Settings == nil {
+		u.Settings = nil
+	}
+	return fmt.Sprintf("%s (%d)", name, *u.Age)
+}
+
+func (u *User) Update(other *User, age int) {
+	u.Name = other.Name
+	u.Age = &age
+	u.Address = nil
+	*u.Age = age // unsafe
+	u.Email = sql.NullString{String: "x"}
+	u.Score = other.Score
+	u.Settings = map[string]any{}
+	u.Settings["theme"] = "light"                 // unsafe
+	if u.Address == nil || u.Address.City == "" { // unsafe
+		return
+	}
+	u.Score.V++
+}
+
+func Names(users []User) []*string {
+	var names []*string
+	for _, u := range users {
+		names = append(names, u.Name) // unsafe
+	}
+	_ = User{1, nil, nil, nil, sql.NullString{}, sql.NullTime{}, sql.Null[float64]{}, nil, nil, nil} // unsafe unsafe unsafe unsafe unsafe unsafe unsafe
+	return names
+}
+
+type Node struct {
+	Value int   `json:"value"`
+	Next  *Node `json:"next"` // unsafe
+}
+
+type Team struct {
+	Lead *Member `json:"lead"` // unsafe
+}
+
+type Member struct {
+	Name *string `json:"name"`
+	Team Team    `json:"team"`
+}
+
+func clearName(name **string) { *name = nil }
+
+func scanEmail(dest sql.Scanner) error { return dest.Scan("x") }
+
+func Reset(u *User, m *Member) error {
+	clearName(&u.Name) // unsafe
+	clearName(&m.Name) // unsafe
+	_, err := fmt.Sscan("1", &u.Age)
+	if err != nil {
+		return err
+	}
+	return scanEmail(&u.Email)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const typxPath = "github.com/pedramktb/go-typx"
+
+// Diagnostic is a finding reported by an analyzer.
+type Diagnostic struct {
+	Pos     token.Pos
+	Check   string
+	Message string
+}
+
+// Pass provides an analyzer with a type-checked package, like analysis.Pass of golang.org/x/tools.
+type Pass struct {
+	Fset      *token.FileSet
+	Files     []*ast.File
+	Pkg       *types.Package
+	TypesInfo *types.Info
+	Report    func(Diagnostic)
+}
+
+// Analyzer describes an analysis function, like analysis.Analyzer of golang.org/x/tools,
+// so that it can be ported to the go/analysis framework without changes to Run.
+type Analyzer struct {
+	Name string
+	Doc  string
+	Run  func(*Pass)
+}
+
+// TypxAnalyzer reports misuse of the typx types.
+var TypxAnalyzer = &Analyzer{
+	Name: "typx",
+	Doc: `report misuse of typx types
+
+The checks are:
+  doublenull:   Nil[*T] and Opt[*T], which double nullability
+  nestedopt:    Opt[Opt[T]], which has no meaning beyond Opt[T]
+  dyncompare:   == and != on Dyn values, which panic for uncomparable values
+  uncheckedval: reading .Val of a Nil or Opt without checking .NotNil or .Set in the same function
+  omitzero:     Opt fields of JSON structs without the omitzero option, which encode unset values
+  dynval:       Dyn{Val: ...} with channels, funcs, complex numbers or unsafe pointers, which cannot be encoded`,
+	Run: run,
+}
+
+func run(pass *Pass) {
+	if pass.Pkg.Path() == typxPath {
+		return
+	}
+	for ident, inst := range pass.TypesInfo.Instances {
+		checkInstance(pass, ident, inst)
+	}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BinaryExpr:
+				checkDynCompare(pass, n)
+			case *ast.StructType:
+				checkOmitzero(pass, n)
+			case *ast.CompositeLit:
+				checkDynVal(pass, n)
+			case *ast.FuncDecl:
+				if n.Body != nil {
+					checkUncheckedVal(pass, n.Body)
+				}
+			case *ast.FuncLit:
+				checkUncheckedVal(pass, n.Body)
+			}
+			return true
+		})
+	}
+}
+
+// typxType returns the name of t if it is an instance of a typx type.
+func typxType(t types.Type) (string, *types.Named) {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != typxPath {
+		return "", nil
+	}
+	return named.Obj().Name(), named
+}
+
+// checkInstance checks the Nil and Opt instances written as type arguments as well as the ones inferred
+// from the results of generic function calls, such as typx.NilFrom(&x).
+func checkInstance(pass *Pass, ident *ast.Ident, inst types.Instance) {
+	switch t := inst.Type.(type) {
+	case *types.Named:
+		checkTypeArgs(pass, ident, t, "")
+	case *types.Signature:
+		for result := range t.Results().Variables() {
+			checkTypeArgs(pass, ident, result.Type(), ident.Name)
+		}
+	}
+}
+
+// checkTypeArgs reports t if it doubles nullability or nests Opt, where fn is the name of the
+// generic function returning t, if any.
+func checkTypeArgs(pass *Pass, ident *ast.Ident, t types.Type, fn string) {
+	name, named := typxType(t)
+	if (name != "Nil" && name != "Opt") || named.TypeArgs().Len() != 1 {
+		return
+	}
+	describe := func(subject, problem string) string {
+		if fn != "" {
+			return fn + " returns " + subject + ", which " + problem
+		}
+		return subject + " " + problem
+	}
+	arg := named.TypeArgs().At(0)
+	if _, ok := types.Unalias(arg).(*types.Pointer); ok {
+		subject := name + "[" + types.TypeString(arg, types.RelativeTo(pass.Pkg)) + "]"
+		pass.Report(Diagnostic{Pos: ident.Pos(), Check: "doublenull", Message: describe(subject, "doubles nullability: use "+name+"[T] or *T")})
+	}
+	if argName, _ := typxType(arg); name == "Opt" && argName == "Opt" {
+		pass.Report(Diagnostic{Pos: ident.Pos(), Check: "nestedopt", Message: describe("Opt[Opt[T]]", "is redundant: use Opt[T], or Opt[Nil[T]] to distinguish null from absent")})
+	}
+}
+
+func checkDynCompare(pass *Pass, expr *ast.BinaryExpr) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return
+	}
+	for _, operand := range []ast.Expr{expr.X, expr.Y} {
+		if name, _ := typxType(pass.TypesInfo.TypeOf(operand)); name == "Dyn" {
+			pass.Report(Diagnostic{Pos: expr.OpPos, Check: "dyncompare", Message: "comparing Dyn values with " + expr.Op.String() + " panics for uncomparable values such as maps and slices: use reflect.DeepEqual or compare their JSON"})
+			return
+		}
+	}
+}
+
+func checkOmitzero(pass *Pass, st *ast.StructType) {
+	if st.Fields == nil {
+		return
+	}
+	hasJSON := false
+	for _, field := range st.Fields.List {
+		if field.Tag != nil && jsonTag(field.Tag) != "" {
+			hasJSON = true
+			break
+		}
+	}
+	if !hasJSON {
+		return
+	}
+	for _, field := range st.Fields.List {
+		if name, _ := typxType(pass.TypesInfo.TypeOf(field.Type)); name != "Opt" {
+			continue
+		}
+		tag := ""
+		if field.Tag != nil {
+			tag = jsonTag(field.Tag)
+		}
+		if tag == "-" {
+			continue
+		}
+		if _, opts, _ := strings.Cut(tag, ","); hasOption(opts, "omitzero") {
+			continue
+		}
+		pass.Report(Diagnostic{Pos: field.Pos(), Check: "omitzero", Message: "Opt field of a JSON struct lacks the omitzero option, so unset values are encoded"})
+	}
+}
+
+func jsonTag(lit *ast.BasicLit) string {
+	tag, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag).Get("json")
+}
+
+func hasOption(opts, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+func checkDynVal(pass *Pass, lit *ast.CompositeLit) {
+	if name, _ := typxType(pass.TypesInfo.TypeOf(lit)); name != "Dyn" || len(lit.Elts) != 1 {
+		return
+	}
+	val := lit.Elts[0]
+	if kv, ok := val.(*ast.KeyValueExpr); ok {
+		val = kv.Value
+	}
+	t := pass.TypesInfo.TypeOf(val)
+	if t == nil {
+		return
+	}
+	var kind string
+	switch u := t.Underlying().(type) {
+	case *types.Chan:
+		kind = "channels"
+	case *types.Signature:
+		kind = "funcs"
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsComplex != 0:
+			kind = "complex numbers"
+		case u.Kind() == types.UnsafePointer:
+			kind = "unsafe pointers"
+		}
+	}
+	if kind != "" {
+		pass.Report(Diagnostic{Pos: val.Pos(), Check: "dynval", Message: "Dyn holds a " + types.TypeString(t, types.RelativeTo(pass.Pkg)) + ", but " + kind + " cannot be encoded as JSON or BSON"})
+	}
+}
+
+// checkUncheckedVal reports reads of x.Val where x is a Nil or Opt and neither x.NotNil nor x.Set
+// is referenced anywhere in the same function body.
+func checkUncheckedVal(pass *Pass, body *ast.BlockStmt) {
+	checked := map[string]bool{}
+	var reads []*ast.SelectorExpr
+	writes := map[*ast.SelectorExpr]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false // checked on its own
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				if sel, ok := ast.Unparen(lhs).(*ast.SelectorExpr); ok {
+					writes[sel] = true
+				}
+			}
+		case *ast.UnaryExpr:
+			if sel, ok := ast.Unparen(n.X).(*ast.SelectorExpr); ok && n.Op == token.AND {
+				writes[sel] = true
+			}
+		case *ast.SelectorExpr:
+			name, _ := typxType(pass.TypesInfo.TypeOf(n.X))
+			switch {
+			case name == "Nil" && n.Sel.Name == "NotNil", name == "Opt" && n.Sel.Name == "Set":
+				checked[types.ExprString(n.X)] = true
+			case (name == "Nil" || name == "Opt") && n.Sel.Name == "Val":
+				reads = append(reads, n)
+			}
+		}
+		return true
+	})
+	for _, sel := range reads {
+		if writes[sel] || checked[types.ExprString(sel.X)] {
+			continue
+		}
+		name, _ := typxType(pass.TypesInfo.TypeOf(sel.X))
+		guard := "NotNil"
+		if name == "Opt" {
+			guard = "Set"
+		}
+		pass.Report(Diagnostic{Pos: sel.Sel.Pos(), Check: "uncheckedval", Message: "reading " + types.ExprString(sel) + " without checking " + types.ExprString(sel.X) + "." + guard})
+	}
+}
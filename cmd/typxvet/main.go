@@ -0,0 +1,159 @@
+// Command typxvet reports misuse of the typx types, such as Nil[*T], comparing Dyn values with ==,
+// or reading .Val without checking .NotNil or .Set.
+//
+// Usage:
+//
+//	typxvet [-checks doublenull,nestedopt,...] [packages]
+//
+// Packages are given as for go list and default to "./...". It exits with status 1 if anything is reported.
+// The analyzer only depends on go/ast and go/types, and mirrors the golang.org/x/tools/go/analysis API.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	checks := flag.String("checks", "", "comma separated checks to run (default all)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: typxvet [flags] [packages]\n\n%s\n\nFlags:\n", TypxAnalyzer.Doc)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	diagnostics, fset, err := vet(flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "typxvet:", err)
+		os.Exit(2)
+	}
+	enabled := map[string]bool{}
+	for _, check := range strings.Split(*checks, ",") {
+		if check != "" {
+			enabled[check] = true
+		}
+	}
+	reported := 0
+	for _, d := range diagnostics {
+		if len(enabled) > 0 && !enabled[d.Check] {
+			continue
+		}
+		fmt.Printf("%s: %s (%s)\n", fset.Position(d.Pos), d.Message, d.Check)
+		reported++
+	}
+	if reported > 0 {
+		os.Exit(1)
+	}
+}
+
+// listedPackage is the subset of the output of go list used to load packages.
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	GoFiles    []string
+	Export     string
+	DepOnly    bool
+	ImportMap  map[string]string
+	Error      *struct{ Err string }
+}
+
+// vet loads the packages matching patterns and runs TypxAnalyzer on them.
+// Dependencies are imported from the export data produced by go list -export.
+func vet(patterns []string) ([]Diagnostic, *token.FileSet, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	args := append([]string{"list", "-e", "-export", "-deps", "-json=ImportPath,Dir,GoFiles,Export,DepOnly,ImportMap,Error"}, patterns...)
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("go list: %w: %s", err, stderr.String())
+	}
+
+	var pkgs []listedPackage
+	exports := map[string]string{}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for dec.More() {
+		var pkg listedPackage
+		if err := dec.Decode(&pkg); err != nil {
+			return nil, nil, fmt.Errorf("go list: %w", err)
+		}
+		if pkg.Export != "" {
+			exports[pkg.ImportPath] = pkg.Export
+		}
+		if !pkg.DepOnly {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+
+	fset := token.NewFileSet()
+	gc := importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		export, ok := exports[path]
+		if !ok {
+			return nil, fmt.Errorf("no export data for %q", path)
+		}
+		return os.Open(export)
+	})
+	var diagnostics []Diagnostic
+	for _, pkg := range pkgs {
+		if pkg.Error != nil {
+			return nil, nil, fmt.Errorf("%s: %s", pkg.ImportPath, pkg.Error.Err)
+		}
+		var files []*ast.File
+		for _, name := range pkg.GoFiles {
+			file, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, parser.ParseComments)
+			if err != nil {
+				return nil, nil, err
+			}
+			files = append(files, file)
+		}
+		info := &types.Info{
+			Types:     map[ast.Expr]types.TypeAndValue{},
+			Defs:      map[*ast.Ident]types.Object{},
+			Uses:      map[*ast.Ident]types.Object{},
+			Instances: map[*ast.Ident]types.Instance{},
+		}
+		conf := types.Config{Importer: importerFunc(func(path string) (*types.Package, error) {
+			if mapped, ok := pkg.ImportMap[path]; ok {
+				path = mapped
+			}
+			return gc.Import(path)
+		})}
+		typesPkg, err := conf.Check(pkg.ImportPath, fset, files, info)
+		if err != nil {
+			return nil, nil, err
+		}
+		TypxAnalyzer.Run(&Pass{
+			Fset:      fset,
+			Files:     files,
+			Pkg:       typesPkg,
+			TypesInfo: info,
+			Report:    func(d Diagnostic) { diagnostics = append(diagnostics, d) },
+		})
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool { return diagnostics[i].Pos < diagnostics[j].Pos })
+	if len(pkgs) == 0 {
+		return nil, nil, errors.New("no packages matched")
+	}
+	return diagnostics, fset, nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
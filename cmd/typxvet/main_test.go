@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var wantPattern = regexp.MustCompile(`// want((?: "[a-z]+")+)`)
+
+func Test_Vet(t *testing.T) {
+	const path = "testdata/src/a/a.go"
+	diagnostics, fset, err := vet([]string{"./testdata/src/a"})
+	require.NoError(t, err)
+
+	got := map[int][]string{}
+	for _, d := range diagnostics {
+		pos := fset.Position(d.Pos)
+		assert.True(t, strings.HasSuffix(pos.Filename, path), pos.Filename)
+		got[pos.Line] = append(got[pos.Line], d.Check)
+		assert.NotEmpty(t, d.Message)
+	}
+	for _, checks := range got {
+		sort.Strings(checks)
+	}
+
+	want := map[int][]string{}
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if m := wantPattern.FindStringSubmatch(scanner.Text()); m != nil {
+			checks := strings.Fields(strings.ReplaceAll(m[1], `"`, ""))
+			sort.Strings(checks)
+			want[line] = checks
+		}
+	}
+	assert.Equal(t, want, got)
+}
+
+func Test_Vet_NoPackages(t *testing.T) {
+	_, _, err := vet([]string{"./testdata/src/missing"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package a
+
+import (
+	"reflect"
+
+	"github.com/pedramktb/go-typx"
+)
+
+type Request struct {
+	Name  typx.Opt[string]  `json:"name"` // want "omitzero"
+	Email typx.Opt[string]  `json:"email,omitzero"`
+	Phone *typx.Opt[string] `json:"phone"`
+	Fax   typx.Opt[string]  `json:"-"`
+	Note  typx.Opt[*string] `json:"note,omitzero"` // want "doublenull"
+}
+
+type Internal struct {
+	Name typx.Opt[string]
+}
+
+var (
+	_ typx.Nil[*int]                  // want "doublenull"
+	_ typx.Opt[typx.Opt[int]]         // want "nestedopt"
+	_ typx.Opt[typx.Nil[int]]         //
+	_ typx.Nil[map[string]any]        //
+	_ = typx.Dyn{Val: make(chan int)} // want "dynval"
+	_ = typx.Dyn{func() {}}           // want "dynval"
+	_ = typx.Dyn{Val: 1i}             // want "dynval"
+	_ = typx.Dyn{Val: []any{1}}
+	_ = typx.NilFrom(new(int))        // want "doublenull"
+	_ = typx.NilFrom[*int](nil)       // want "doublenull"
+	_ = typx.OptFrom(typx.OptFrom(1)) // want "nestedopt"
+	_ = typx.NilFromPtr(new(int))
+)
+
+func Compare(a, b typx.Dyn) bool {
+	if a == b { // want "dyncompare"
+		return true
+	}
+	return reflect.DeepEqual(a.Val, b.Val)
+}
+
+func Unchecked(n typx.Nil[int], o typx.Opt[string]) (int, string) {
+	return n.Val, o.Val // want "uncheckedval" "uncheckedval"
+}
+
+func Checked(n typx.Nil[int], o typx.Opt[string]) (int, string) {
+	if !n.NotNil || !o.Set {
+		return 0, ""
+	}
+	return n.Val, o.Val
+}
+
+func Write(n *typx.Nil[int]) *int {
+	n.Val = 1
+	n.NotNil = true
+	p := &n.Val
+	return p
+}
+
+func Closure(r Request) func() string {
+	if !r.Email.Set {
+		return nil
+	}
+	return func() string {
+		return r.Email.Val // want "uncheckedval"
+	}
+}
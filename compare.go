@@ -0,0 +1,64 @@
+package typx
+
+import (
+	"cmp"
+	"reflect"
+)
+
+// NilCompare compares a and b like cmp.Compare, ordering nil values before (nullsFirst) or after
+// all non-nil values, like ORDER BY ... NULLS FIRST/LAST in SQL.
+func NilCompare[T cmp.Ordered](a, b Nil[T], nullsFirst bool) int {
+	return NilCompareFunc(a, b, nullsFirst, cmp.Compare[T])
+}
+
+// NilCompareFunc is like NilCompare but compares non-nil values with compare, e.g. time.Time.Compare.
+func NilCompareFunc[T any](a, b Nil[T], nullsFirst bool, compare func(T, T) int) int {
+	return comparePresence(a.NotNil, b.NotNil, nullsFirst, func() int { return compare(a.Val, b.Val) })
+}
+
+// OptCompare compares a and b like cmp.Compare, ordering unset values before (unsetFirst) or after all set values.
+func OptCompare[T cmp.Ordered](a, b Opt[T], unsetFirst bool) int {
+	return OptCompareFunc(a, b, unsetFirst, cmp.Compare[T])
+}
+
+// OptCompareFunc is like OptCompare but compares set values with compare.
+func OptCompareFunc[T any](a, b Opt[T], unsetFirst bool, compare func(T, T) int) int {
+	return comparePresence(a.Set, b.Set, unsetFirst, func() int { return compare(a.Val, b.Val) })
+}
+
+func comparePresence(aPresent, bPresent, absentFirst bool, compare func() int) int {
+	switch {
+	case aPresent && bPresent:
+		return compare()
+	case aPresent == bPresent:
+		return 0
+	case aPresent == absentFirst:
+		return 1
+	}
+	return -1
+}
+
+// Equal reports whether both are nil or both hold equal values.
+// Values are compared with their Equal(T) bool method if any, like time.Time, or with reflect.DeepEqual.
+func (n Nil[T]) Equal(other Nil[T]) bool {
+	if !n.NotNil || !other.NotNil {
+		return n.NotNil == other.NotNil
+	}
+	return valuesEqual(n.Val, other.Val)
+}
+
+// Equal reports whether both are unset or both hold equal values.
+// Values are compared with their Equal(T) bool method if any, like time.Time, or with reflect.DeepEqual.
+func (o Opt[T]) Equal(other Opt[T]) bool {
+	if !o.Set || !other.Set {
+		return o.Set == other.Set
+	}
+	return valuesEqual(o.Val, other.Val)
+}
+
+func valuesEqual[T any](a, b T) bool {
+	if eq, ok := any(a).(interface{ Equal(T) bool }); ok {
+		return eq.Equal(b)
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,53 @@
+package typx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NilCompare(t *testing.T) {
+	tests := []struct {
+		name       string
+		a, b       typx.Nil[int]
+		nullsFirst bool
+		want       int
+	}{
+		{name: "values less", a: typx.NilFrom(1), b: typx.NilFrom(2), want: -1},
+		{name: "values equal", a: typx.NilFrom(2), b: typx.NilFrom(2), want: 0},
+		{name: "values greater", a: typx.NilFrom(3), b: typx.NilFrom(2), nullsFirst: true, want: 1},
+		{name: "nulls", a: typx.Nil[int]{}, b: typx.Nil[int]{Val: 1}, want: 0},
+		{name: "null first", a: typx.Nil[int]{}, b: typx.NilFrom(-1), nullsFirst: true, want: -1},
+		{name: "null last", a: typx.Nil[int]{}, b: typx.NilFrom(-1), want: 1},
+		{name: "value before null", a: typx.NilFrom(1), b: typx.Nil[int]{}, want: -1},
+		{name: "value after null", a: typx.NilFrom(1), b: typx.Nil[int]{}, nullsFirst: true, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, typx.NilCompare(tt.a, tt.b, tt.nullsFirst))
+			assert.Equal(t, tt.want, typx.OptCompare(typx.OptFromPtr(tt.a.Ptr()), typx.OptFromPtr(tt.b.Ptr()), tt.nullsFirst))
+		})
+	}
+
+	now := time.Now()
+	assert.Equal(t, -1, typx.NilCompareFunc(typx.NilFrom(now), typx.NilFrom(now.Add(time.Second)), false, time.Time.Compare))
+	assert.Equal(t, 1, typx.OptCompareFunc(typx.Opt[time.Time]{}, typx.OptFrom(now), false, time.Time.Compare))
+}
+
+func Test_Equal(t *testing.T) {
+	assert.True(t, typx.Nil[int]{Val: 1}.Equal(typx.Nil[int]{}))
+	assert.True(t, typx.NilFrom(1).Equal(typx.NilFrom(1)))
+	assert.False(t, typx.NilFrom(0).Equal(typx.Nil[int]{}))
+	assert.False(t, typx.NilFrom(1).Equal(typx.NilFrom(2)))
+	assert.True(t, typx.NilFrom([]int{1}).Equal(typx.NilFrom([]int{1})))
+
+	now := time.Now()
+	assert.True(t, typx.NilFrom(now).Equal(typx.NilFrom(now.UTC())))
+
+	assert.True(t, typx.Opt[int]{Val: 1}.Equal(typx.Opt[int]{}))
+	assert.True(t, typx.OptFrom("a").Equal(typx.OptFrom("a")))
+	assert.False(t, typx.OptFrom("").Equal(typx.Opt[string]{}))
+	assert.True(t, typx.OptFrom(typx.Nil[int]{}).Equal(typx.OptFrom(typx.Nil[int]{Val: 1})))
+}
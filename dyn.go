@@ -5,6 +5,8 @@ import (
 	"database/sql/driver"
 	"encoding"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -43,8 +45,9 @@ func (d *Dyn) Scan(src any) error {
 }
 
 // Value implements the driver.Valuer interface.
+// It returns JSON as []byte and JSON null for nil values, see DynAs and DynSQLConfig for other settings.
 func (d Dyn) Value() (driver.Value, error) {
-	return json.Marshal(d.Val)
+	return DynSQLConfig{}.Value(d)
 }
 
 // MarshalBSONValue implements the bson.ValueMarshaler interface.
@@ -87,27 +90,142 @@ func convertBSONToNative(v any) any {
 	}
 }
 
+// MarshalCBOR encodes the value as self-describing RFC 8949 CBOR.
+// Unlike JSON, it preserves integers vs floats, byte strings and timestamps.
+func (d Dyn) MarshalCBOR() ([]byte, error) {
+	return marshalCBOR(d.Val)
+}
+
+// UnmarshalCBOR decodes RFC 8949 CBOR into a JSON-shaped value,
+// keeping integers as int64, byte strings as []byte and timestamps as time.Time.
+func (d *Dyn) UnmarshalCBOR(data []byte) error {
+	val, err := unmarshalCBOR(data)
+	if err != nil {
+		return err
+	}
+	d.Val = val
+	return nil
+}
+
+// DynCBOR is a Dyn whose MarshalBinary and UnmarshalBinary fall back to CBOR
+// when the underlying type does not implement the respective interfaces.
+// It can be used in place of Dyn as a struct field or a value that is stored in binary form.
+type DynCBOR struct {
+	Dyn
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, falling back to Dyn.MarshalCBOR.
+func (d DynCBOR) MarshalBinary() ([]byte, error) {
+	return d.marshalBinary(true)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, falling back to Dyn.UnmarshalCBOR.
+func (d *DynCBOR) UnmarshalBinary(data []byte) error {
+	return d.unmarshalBinary(data, true)
+}
+
+// MarshalMsgpack implements the MsgpackMarshaler interface.
+// Maps, slices and time.Time values are encoded as msgpack maps, arrays and timestamp extensions.
+func (d Dyn) MarshalMsgpack() ([]byte, error) {
+	return MarshalMsgpack(d.Val)
+}
+
+// UnmarshalMsgpack implements the MsgpackUnmarshaler interface.
+// Integers are decoded as int64, binary data as []byte and timestamps as time.Time.
+func (d *Dyn) UnmarshalMsgpack(data []byte) error {
+	val, err := unmarshalMsgpackAny(data)
+	if err != nil {
+		return err
+	}
+	d.Val = val
+	return nil
+}
+
+// MarshalXML implements the xml.Marshaler interface using the following convention:
+//   - objects become child elements named after their keys, which must be valid XML names
+//   - arrays become repeated <item> child elements
+//   - nil becomes an empty element with the xsi:nil="true" attribute
+//   - objects, arrays, numbers and booleans are marked with a type attribute
+//     (type="object", "array", "number" or "boolean"), strings have no type attribute
+func (d Dyn) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	tree, err := dynJSONTree(d.Val)
+	if err != nil {
+		return err
+	}
+	return marshalDynXML(e, start, tree)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface following the convention of MarshalXML.
+// Elements without a type attribute are decoded as strings, or as objects if they have child elements.
+func (d *Dyn) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	val, err := unmarshalDynXML(dec, start)
+	if err != nil {
+		return err
+	}
+	d.Val = val
+	return nil
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface by encoding the value as compact JSON.
+func (d Dyn) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	data, err := json.Marshal(d.Val)
+	if err != nil {
+		return xml.Attr{}, err
+	}
+	return xml.Attr{Name: name, Value: string(data)}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface by decoding the value as JSON.
+func (d *Dyn) UnmarshalXMLAttr(attr xml.Attr) error {
+	return json.Unmarshal([]byte(attr.Value), &d.Val)
+}
+
 // The following implementations are provided for convenience,
-// but they require that the underlying type implements the respective interfaces.
+// but they require that the underlying type implements the respective interfaces
+// (unless it is registered with RegisterDynType, or wrapped in DynCBOR for the binary ones).
+// Registered types are encoded in an envelope carrying their type name instead.
 
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
 func (d Dyn) MarshalBinary() ([]byte, error) {
+	return d.marshalBinary(false)
+}
+
+func (d Dyn) marshalBinary(cbor bool) ([]byte, error) {
+	if name, ok := dynTypeName(d.Val); ok {
+		return marshalDynEnvelope(name, d.Val)
+	}
 	if marshaler, ok := d.Val.(encoding.BinaryMarshaler); ok {
 		return marshaler.MarshalBinary()
 	}
+	if cbor {
+		return d.MarshalCBOR()
+	}
 	return nil, fmt.Errorf("type %T does not implement encoding.BinaryMarshaler", d.Val)
 }
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (d *Dyn) UnmarshalBinary(data []byte) error {
+	return d.unmarshalBinary(data, false)
+}
+
+func (d *Dyn) unmarshalBinary(data []byte, cbor bool) error {
+	if isDynEnvelope(data) {
+		return d.unmarshalEnvelope(data)
+	}
 	if unmarshaler, ok := d.Val.(encoding.BinaryUnmarshaler); ok {
 		return unmarshaler.UnmarshalBinary(data)
 	}
+	if cbor {
+		return d.UnmarshalCBOR(data)
+	}
 	return fmt.Errorf("type %T does not implement encoding.BinaryUnmarshaler", d.Val)
 }
 
 // MarshalText implements the encoding.TextMarshaler interface.
 func (d Dyn) MarshalText() ([]byte, error) {
+	if name, ok := dynTypeName(d.Val); ok {
+		return marshalDynEnvelope(name, d.Val)
+	}
 	if marshaler, ok := d.Val.(encoding.TextMarshaler); ok {
 		return marshaler.MarshalText()
 	}
@@ -116,8 +234,53 @@ func (d Dyn) MarshalText() ([]byte, error) {
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
 func (d *Dyn) UnmarshalText(data []byte) error {
+	if isDynEnvelope(data) {
+		return d.unmarshalEnvelope(data)
+	}
 	if unmarshaler, ok := d.Val.(encoding.TextUnmarshaler); ok {
 		return unmarshaler.UnmarshalText(data)
 	}
 	return fmt.Errorf("type %T does not implement encoding.TextUnmarshaler", d.Val)
 }
+
+func (d *Dyn) unmarshalEnvelope(data []byte) error {
+	val, err := unmarshalDynEnvelope(data)
+	if err != nil {
+		return err
+	}
+	d.Val = val
+	return nil
+}
+
+// Gob encoding kinds of Dyn.
+const (
+	dynGobJSON     byte = 0
+	dynGobEnvelope byte = 1
+)
+
+// GobEncode implements the gob.GobEncoder interface.
+// Registered types are encoded in an envelope carrying their type name,
+// while other values are encoded as JSON and decoded into the same trees as Dyn.UnmarshalJSON.
+func (d Dyn) GobEncode() ([]byte, error) {
+	if name, ok := dynTypeName(d.Val); ok {
+		data, err := marshalDynEnvelope(name, d.Val)
+		return append([]byte{dynGobEnvelope}, data...), err
+	}
+	data, err := d.MarshalJSON()
+	return append([]byte{dynGobJSON}, data...), err
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (d *Dyn) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("cannot gob decode Dyn: empty data")
+	}
+	switch data[0] {
+	case dynGobJSON:
+		d.Val = nil
+		return d.UnmarshalJSON(data[1:])
+	case dynGobEnvelope:
+		return d.unmarshalEnvelope(data[1:])
+	}
+	return fmt.Errorf("cannot gob decode Dyn: unknown encoding kind %d", data[0])
+}
@@ -0,0 +1,103 @@
+package typx
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// DynNullPolicy decides how a nil Dyn is stored in SQL.
+type DynNullPolicy uint8
+
+const (
+	// DynNullJSON stores a nil Dyn as the JSON null literal.
+	DynNullJSON DynNullPolicy = iota
+	// DynNullSQL stores a nil Dyn as SQL NULL.
+	DynNullSQL
+)
+
+// SQLDialect decides the driver.Value type a Dyn is stored as.
+type SQLDialect uint8
+
+const (
+	// DialectGeneric stores JSON as []byte.
+	DialectGeneric SQLDialect = iota
+	// DialectPostgresJSON stores JSON as string for JSON columns,
+	// since some drivers send []byte parameters as bytea.
+	DialectPostgresJSON
+	// DialectPostgresJSONB stores JSON as string for JSONB columns,
+	// and rejects \u0000 escapes which JSONB cannot store.
+	DialectPostgresJSONB
+	// DialectMySQLJSON stores JSON as string for JSON columns,
+	// since []byte parameters are sent with the binary character set.
+	DialectMySQLJSON
+	// DialectSQLiteText stores JSON as string for TEXT columns, as []byte would be stored as a BLOB.
+	DialectSQLiteText
+)
+
+// DynSQLConfig holds the SQL encoding settings of Dyn.
+type DynSQLConfig struct {
+	Null    DynNullPolicy
+	Dialect SQLDialect
+}
+
+// DynSQLValue is a Dyn that is stored in SQL according to its own Config instead of the defaults of Dyn.Value.
+// It can be used in place of Dyn as a struct field or a query argument of columns that need other settings,
+// and scans like Dyn.
+type DynSQLValue struct {
+	Dyn
+	Config DynSQLConfig
+}
+
+// DynAs returns d with the SQL settings c.
+func DynAs(d Dyn, c DynSQLConfig) DynSQLValue {
+	return DynSQLValue{Dyn: d, Config: c}
+}
+
+// Value implements the driver.Valuer interface according to the Config.
+func (v DynSQLValue) Value() (driver.Value, error) {
+	return v.Config.Value(v.Dyn)
+}
+
+var errJSONBNullChar = errors.New(`cannot store Dyn as JSONB: \u0000 is not supported in JSONB text`)
+
+// hasJSONNullChar reports whether the JSON text data holds a \u0000 escape, skipping the other escapes,
+// so that an escaped backslash followed by u0000 (a string without a NUL character) is not mistaken for one.
+func hasJSONNullChar(data []byte) bool {
+	for i := 0; i < len(data); i++ {
+		if data[i] != '\\' {
+			continue
+		}
+		if bytes.HasPrefix(data[i+1:], []byte("u0000")) {
+			return true
+		}
+		i++ // skip the escaped character, which may be another backslash
+	}
+	return false
+}
+
+// Value returns the driver.Value of d according to the settings.
+// Dyn.Scan accepts the results of every setting.
+func (c DynSQLConfig) Value(d Dyn) (driver.Value, error) {
+	if d.Val == nil && c.Null == DynNullSQL {
+		return nil, nil
+	}
+	data, err := json.Marshal(d.Val)
+	if err != nil {
+		return nil, err
+	}
+	if c.Null == DynNullSQL && bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	switch c.Dialect {
+	case DialectPostgresJSONB:
+		if hasJSONNullChar(data) {
+			return nil, errJSONBNullChar
+		}
+		return string(data), nil
+	case DialectPostgresJSON, DialectMySQLJSON, DialectSQLiteText:
+		return string(data), nil
+	}
+	return data, nil
+}
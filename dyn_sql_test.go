@@ -0,0 +1,133 @@
+package typx_test
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DynSQLConfig_Value(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  typx.DynSQLConfig
+		value   typx.Dyn
+		want    driver.Value
+		wantErr bool
+	}{
+		{
+			name:  "default nil",
+			value: typx.Dyn{Val: nil},
+			want:  []byte("null"),
+		},
+		{
+			name:   "sql null nil",
+			config: typx.DynSQLConfig{Null: typx.DynNullSQL},
+			value:  typx.Dyn{Val: nil},
+			want:   nil,
+		},
+		{
+			name:   "sql null typed nil",
+			config: typx.DynSQLConfig{Null: typx.DynNullSQL},
+			value:  typx.Dyn{Val: (*string)(nil)},
+			want:   nil,
+		},
+		{
+			name:   "sql null value",
+			config: typx.DynSQLConfig{Null: typx.DynNullSQL},
+			value:  typx.Dyn{Val: map[string]any{"a": 1}},
+			want:   []byte(`{"a":1}`),
+		},
+		{
+			name:   "postgres json",
+			config: typx.DynSQLConfig{Dialect: typx.DialectPostgresJSON},
+			value:  typx.Dyn{Val: []any{1, "a"}},
+			want:   `[1,"a"]`,
+		},
+		{
+			name:   "postgres jsonb",
+			config: typx.DynSQLConfig{Dialect: typx.DialectPostgresJSONB},
+			value:  typx.Dyn{Val: nil},
+			want:   "null",
+		},
+		{
+			name:    "postgres jsonb null character",
+			config:  typx.DynSQLConfig{Dialect: typx.DialectPostgresJSONB},
+			value:   typx.Dyn{Val: "a\x00b"},
+			wantErr: true,
+		},
+		{
+			name:    "postgres jsonb null character in a key",
+			config:  typx.DynSQLConfig{Dialect: typx.DialectPostgresJSONB},
+			value:   typx.Dyn{Val: map[string]any{"\\\x00": 1}},
+			wantErr: true,
+		},
+		{
+			name:   "postgres jsonb escaped backslash before u0000",
+			config: typx.DynSQLConfig{Dialect: typx.DialectPostgresJSONB},
+			value:  typx.Dyn{Val: `a\u0000b`},
+			want:   `"a\\u0000b"`,
+		},
+		{
+			name:   "mysql json",
+			config: typx.DynSQLConfig{Dialect: typx.DialectMySQLJSON, Null: typx.DynNullSQL},
+			value:  typx.Dyn{Val: true},
+			want:   "true",
+		},
+		{
+			name:   "sqlite text",
+			config: typx.DynSQLConfig{Dialect: typx.DialectSQLiteText},
+			value:  typx.Dyn{Val: "example"},
+			want:   `"example"`,
+		},
+		{
+			name:    "unsupported value",
+			value:   typx.Dyn{Val: make(chan int)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.Value(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			scanned := typx.Dyn{Val: "previous"}
+			assert.NoError(t, scanned.Scan(got))
+			want := typx.Dyn{}
+			assert.NoError(t, want.UnmarshalJSON([]byte(mustJSON(t, tt.value))))
+			assert.Equal(t, want, scanned)
+		})
+	}
+}
+
+func Test_DynAs(t *testing.T) {
+	got, err := typx.Dyn{Val: nil}.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("null"), got)
+
+	config := typx.DynSQLConfig{Null: typx.DynNullSQL, Dialect: typx.DialectSQLiteText}
+	got, err = typx.DynAs(typx.Dyn{Val: nil}, config).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = typx.DynAs(typx.Dyn{Val: 1}, config).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "1", got)
+
+	scanned := typx.DynSQLValue{Config: config}
+	assert.NoError(t, scanned.Scan(got))
+	assert.Equal(t, typx.DynAs(typx.Dyn{Val: float64(1)}, config), scanned)
+}
+
+func mustJSON(t *testing.T, d typx.Dyn) string {
+	t.Helper()
+	data, err := d.MarshalJSON()
+	assert.NoError(t, err)
+	return string(data)
+}
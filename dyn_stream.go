@@ -0,0 +1,173 @@
+package typx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// StreamDyn lazily decodes the top-level JSON array or object read from r.
+// Array elements are yielded one by one, and object members are yielded as a Dyn holding
+// a single-entry map[string]any of the member key and value.
+// Memory use is bounded by the largest single element rather than by the whole document.
+// Iteration stops at the first error, which is yielded along with an empty Dyn.
+func StreamDyn(r io.Reader) iter.Seq2[Dyn, error] {
+	return StreamDynAt(r, "")
+}
+
+// StreamDynAt is like StreamDyn, but streams the array or object found at the given
+// RFC 6901 JSON Pointer (e.g. "/data/items") instead of the top-level value.
+// Values preceding the target are skipped token by token without being materialized.
+func StreamDynAt(r io.Reader, pointer string) iter.Seq2[Dyn, error] {
+	return func(yield func(Dyn, error) bool) {
+		dec := json.NewDecoder(r)
+		if err := seekPointer(dec, pointer); err != nil {
+			yield(Dyn{}, err)
+			return
+		}
+		tok, err := dec.Token()
+		if err != nil {
+			yield(Dyn{}, err)
+			return
+		}
+		switch tok {
+		case json.Delim('['):
+			for dec.More() {
+				var d Dyn
+				if err := dec.Decode(&d); err != nil {
+					yield(Dyn{}, err)
+					return
+				}
+				if !yield(d, nil) {
+					return
+				}
+			}
+		case json.Delim('{'):
+			for dec.More() {
+				key, err := objectKey(dec)
+				if err != nil {
+					yield(Dyn{}, err)
+					return
+				}
+				var d Dyn
+				if err := dec.Decode(&d); err != nil {
+					yield(Dyn{}, err)
+					return
+				}
+				if !yield(Dyn{Val: map[string]any{key: d.Val}}, nil) {
+					return
+				}
+			}
+		default:
+			yield(Dyn{}, fmt.Errorf("cannot stream %v at %q: expected JSON array or object", tok, pointer))
+			return
+		}
+		if _, err := dec.Token(); err != nil {
+			yield(Dyn{}, err)
+		}
+	}
+}
+
+// seekPointer advances dec so that the next token is the start of the value at pointer.
+func seekPointer(dec *json.Decoder, pointer string) error {
+	if pointer == "" {
+		return nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return fmt.Errorf("invalid JSON pointer %q: must be empty or start with '/'", pointer)
+	}
+	for _, ref := range strings.Split(pointer[1:], "/") {
+		ref = strings.NewReplacer("~1", "/", "~0", "~").Replace(ref)
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			if err := seekMember(dec, ref); err != nil {
+				return fmt.Errorf("cannot resolve JSON pointer %q: %w", pointer, err)
+			}
+		case json.Delim('['):
+			if err := seekElement(dec, ref); err != nil {
+				return fmt.Errorf("cannot resolve JSON pointer %q: %w", pointer, err)
+			}
+		default:
+			return fmt.Errorf("cannot resolve JSON pointer %q: reference %q into scalar %v", pointer, ref, tok)
+		}
+	}
+	return nil
+}
+
+// seekMember skips object members until the one with the given key is next.
+func seekMember(dec *json.Decoder, key string) error {
+	for dec.More() {
+		k, err := objectKey(dec)
+		if err != nil {
+			return err
+		}
+		if k == key {
+			return nil
+		}
+		if err := skipValue(dec); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("member %q not found", key)
+}
+
+// seekElement skips array elements until the one at the given index is next.
+func seekElement(dec *json.Decoder, ref string) error {
+	idx, err := strconv.Atoi(ref)
+	if err != nil || idx < 0 || (len(ref) > 1 && ref[0] == '0') {
+		return fmt.Errorf("invalid array index %q", ref)
+	}
+	for i := 0; dec.More(); i++ {
+		if i == idx {
+			return nil
+		}
+		if err := skipValue(dec); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("array index %d out of range", idx)
+}
+
+// objectKey reads the next object key from dec.
+func objectKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected token %v: expected object key", tok)
+	}
+	return key, nil
+}
+
+// skipValue consumes the next value from dec without materializing it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
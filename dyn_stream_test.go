@@ -0,0 +1,110 @@
+package typx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StreamDyn(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		pointer string
+		want    []typx.Dyn
+		wantErr bool
+	}{
+		{
+			name: "top-level array",
+			data: `[1,"two",{"three":3},[4]]`,
+			want: []typx.Dyn{
+				{Val: float64(1)},
+				{Val: "two"},
+				{Val: map[string]any{"three": float64(3)}},
+				{Val: []any{float64(4)}},
+			},
+		},
+		{
+			name: "top-level object",
+			data: `{"a":1,"b":null}`,
+			want: []typx.Dyn{
+				{Val: map[string]any{"a": float64(1)}},
+				{Val: map[string]any{"b": nil}},
+			},
+		},
+		{
+			name:    "nested array",
+			data:    `{"meta":{"skip":[1,2,{"x":[]}]},"data":{"items":[true,false]}}`,
+			pointer: "/data/items",
+			want:    []typx.Dyn{{Val: true}, {Val: false}},
+		},
+		{
+			name:    "array index and escaped key",
+			data:    `[{"a/b":[0]},{"a/b":[1,2]}]`,
+			pointer: "/1/a~1b",
+			want:    []typx.Dyn{{Val: float64(1)}, {Val: float64(2)}},
+		},
+		{
+			name:    "empty array",
+			data:    `{"items":[]}`,
+			pointer: "/items",
+			want:    nil,
+		},
+		{
+			name:    "scalar target",
+			data:    `{"items":42}`,
+			pointer: "/items",
+			wantErr: true,
+		},
+		{
+			name:    "missing member",
+			data:    `{"items":[]}`,
+			pointer: "/other",
+			wantErr: true,
+		},
+		{
+			name:    "invalid pointer",
+			data:    `[]`,
+			pointer: "items",
+			wantErr: true,
+		},
+		{
+			name:    "truncated input",
+			data:    `[1,2,`,
+			want:    []typx.Dyn{{Val: float64(1)}, {Val: float64(2)}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []typx.Dyn
+			var gotErr error
+			for d, err := range typx.StreamDynAt(strings.NewReader(tt.data), tt.pointer) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				got = append(got, d)
+			}
+			if tt.wantErr {
+				assert.Error(t, gotErr)
+			} else {
+				assert.NoError(t, gotErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_StreamDyn_Break(t *testing.T) {
+	count := 0
+	for d, err := range typx.StreamDyn(strings.NewReader(`[1,2,3]`)) {
+		assert.NoError(t, err)
+		assert.Equal(t, typx.Dyn{Val: float64(1)}, d)
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
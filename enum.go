@@ -0,0 +1,235 @@
+package typx
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Enum is a value of E restricted to the values registered with RegisterEnum.
+// It is encoded by the registered name of its value, and decoding rejects unknown names
+// unless a fallback was registered with RegisterEnumFallback.
+// It implements interfaces for SQL, JSON, text and BSON encoding.
+type Enum[E comparable] struct {
+	Val E
+}
+
+type enumSpec struct {
+	values   []any
+	names    []string
+	byName   map[string]any
+	byValue  map[any]string
+	fallback any
+}
+
+var enumRegistry = struct {
+	sync.RWMutex
+	specs map[reflect.Type]*enumSpec
+}{specs: map[reflect.Type]*enumSpec{}}
+
+// RegisterEnum registers the allowed values of E in order. Values are named by fmt.Sprint,
+// so named string types use their value and other types can define a String method.
+// Like RegisterDynType, it should be called during initialization and panics if E is already registered
+// or if two values share a name.
+func RegisterEnum[E comparable](values ...E) {
+	t := reflect.TypeFor[E]()
+	spec := &enumSpec{byName: map[string]any{}, byValue: map[any]string{}}
+	for _, v := range values {
+		name := fmt.Sprint(v)
+		if _, ok := spec.byName[name]; ok {
+			panic(fmt.Sprintf("typx: registering duplicate enum name %q for %s", name, t))
+		}
+		spec.values = append(spec.values, v)
+		spec.names = append(spec.names, name)
+		spec.byName[name] = v
+		spec.byValue[v] = name
+	}
+	enumRegistry.Lock()
+	defer enumRegistry.Unlock()
+	if _, ok := enumRegistry.specs[t]; ok {
+		panic(fmt.Sprintf("typx: registering duplicate enum %s", t))
+	}
+	enumRegistry.specs[t] = spec
+}
+
+// RegisterEnumFallback makes decoding unknown names of E result in fallback instead of an error,
+// for forward compatibility with values added by newer producers. The fallback must be a registered value.
+func RegisterEnumFallback[E comparable](fallback E) {
+	t := reflect.TypeFor[E]()
+	enumRegistry.Lock()
+	defer enumRegistry.Unlock()
+	spec, ok := enumRegistry.specs[t]
+	if !ok {
+		panic(fmt.Sprintf("typx: registering a fallback for unregistered enum %s", t))
+	}
+	if _, ok := spec.byValue[fallback]; !ok {
+		panic(fmt.Sprintf("typx: registering unknown fallback %v for enum %s", fallback, t))
+	}
+	spec.fallback = fallback
+}
+
+func enumSpecOf[E comparable]() (*enumSpec, error) {
+	enumRegistry.RLock()
+	defer enumRegistry.RUnlock()
+	spec, ok := enumRegistry.specs[reflect.TypeFor[E]()]
+	if !ok {
+		return nil, fmt.Errorf("enum %s is not registered", reflect.TypeFor[E]())
+	}
+	return spec, nil
+}
+
+// EnumFrom returns an Enum with the value v, or an error if v is not registered.
+func EnumFrom[E comparable](v E) (Enum[E], error) {
+	e := Enum[E]{Val: v}
+	if _, err := e.name(); err != nil {
+		return Enum[E]{}, err
+	}
+	return e, nil
+}
+
+// EnumParse returns the Enum registered under name, applying the fallback if any.
+func EnumParse[E comparable](name string) (Enum[E], error) {
+	spec, err := enumSpecOf[E]()
+	if err != nil {
+		return Enum[E]{}, err
+	}
+	v, ok := spec.byName[name]
+	if !ok {
+		if spec.fallback == nil {
+			return Enum[E]{}, fmt.Errorf("unknown %s %q: expected one of %q", reflect.TypeFor[E](), name, spec.names)
+		}
+		v = spec.fallback
+	}
+	return Enum[E]{Val: v.(E)}, nil
+}
+
+func (e Enum[E]) name() (string, error) {
+	spec, err := enumSpecOf[E]()
+	if err != nil {
+		return "", err
+	}
+	name, ok := spec.byValue[e.Val]
+	if !ok {
+		return "", fmt.Errorf("unknown %s %v: expected one of %q", reflect.TypeFor[E](), e.Val, spec.names)
+	}
+	return name, nil
+}
+
+// Valid reports whether the value is registered.
+func (e Enum[E]) Valid() bool {
+	_, err := e.name()
+	return err == nil
+}
+
+// String returns the registered name of the value, or its fmt.Sprint form if it is not registered.
+func (e Enum[E]) String() string {
+	if name, err := e.name(); err == nil {
+		return name
+	}
+	return fmt.Sprint(e.Val)
+}
+
+// Values returns the registered values of E in registration order, e.g. for API documentation.
+func (Enum[E]) Values() []E {
+	spec, err := enumSpecOf[E]()
+	if err != nil {
+		return nil
+	}
+	values := make([]E, len(spec.values))
+	for i, v := range spec.values {
+		values[i] = v.(E)
+	}
+	return values
+}
+
+// Names returns the registered names of E in registration order.
+func (Enum[E]) Names() []string {
+	spec, err := enumSpecOf[E]()
+	if err != nil {
+		return nil
+	}
+	return slices.Clone(spec.names)
+}
+
+// Scan implements the sql.Scanner interface. Use Nil[Enum[E]] for nullable columns.
+func (e *Enum[E]) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return e.UnmarshalText([]byte(v))
+	case []byte:
+		return e.UnmarshalText(v)
+	}
+	return fmt.Errorf("cannot scan %T into Enum: expected a name ([]byte or string)", src)
+}
+
+// Value implements the driver.Valuer interface, returning the registered name.
+func (e Enum[E]) Value() (driver.Value, error) {
+	return e.name()
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (e Enum[E]) MarshalText() ([]byte, error) {
+	name, err := e.name()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (e *Enum[E]) UnmarshalText(text []byte) error {
+	parsed, err := EnumParse[E](string(text))
+	if err != nil {
+		return err
+	}
+	*e = parsed
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the registered name as a string.
+func (e Enum[E]) MarshalJSON() ([]byte, error) {
+	name, err := e.name()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(name)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Like other decoders, it ignores null.
+func (e *Enum[E]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("cannot unmarshal %s into Enum: expected a string", data)
+	}
+	return e.UnmarshalText([]byte(name))
+}
+
+// MarshalBSONValue implements the bson.ValueMarshaler interface, encoding the registered name as a string.
+func (e Enum[E]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	name, err := e.name()
+	if err != nil {
+		return 0, nil, err
+	}
+	return bson.MarshalValue(name)
+}
+
+// UnmarshalBSONValue implements the bson.ValueUnmarshaler interface.
+func (e *Enum[E]) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t != bson.TypeString {
+		return fmt.Errorf("cannot unmarshal BSON %s into Enum: expected a string", t)
+	}
+	var name string
+	if err := bson.UnmarshalValue(t, data, &name); err != nil {
+		return err
+	}
+	return e.UnmarshalText([]byte(name))
+}
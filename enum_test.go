@@ -0,0 +1,120 @@
+package typx_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type color string
+
+type status int
+
+const (
+	statusUnknown status = iota
+	statusActive
+	statusBlocked
+)
+
+func (s status) String() string {
+	return [...]string{"unknown", "active", "blocked"}[s]
+}
+
+func init() {
+	typx.RegisterEnum[color]("red", "green")
+	typx.RegisterEnum(statusUnknown, statusActive, statusBlocked)
+	typx.RegisterEnumFallback(statusUnknown)
+}
+
+func Test_Enum_Registry(t *testing.T) {
+	assert.Equal(t, []color{"red", "green"}, typx.Enum[color]{}.Values())
+	assert.Equal(t, []string{"unknown", "active", "blocked"}, typx.Enum[status]{}.Names())
+
+	red, err := typx.EnumFrom[color]("red")
+	assert.NoError(t, err)
+	assert.True(t, red.Valid())
+	assert.Equal(t, "red", red.String())
+
+	_, err = typx.EnumFrom[color]("blue")
+	assert.Error(t, err)
+	assert.False(t, typx.Enum[color]{Val: "blue"}.Valid())
+
+	active, err := typx.EnumParse[status]("active")
+	assert.NoError(t, err)
+	assert.Equal(t, typx.Enum[status]{Val: statusActive}, active)
+
+	_, err = typx.EnumParse[int]("1")
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { typx.RegisterEnum[color]("blue") })
+	assert.Panics(t, func() { typx.RegisterEnum("a", "a") })
+	assert.Panics(t, func() { typx.RegisterEnumFallback[color]("blue") })
+	assert.Panics(t, func() { typx.RegisterEnumFallback(1.5) })
+}
+
+func Test_Enum_JSON(t *testing.T) {
+	type doc struct {
+		Color  typx.Enum[color]            `json:"color"`
+		Status typx.Nil[typx.Enum[status]] `json:"status"`
+	}
+	data, err := json.Marshal(doc{Color: typx.Enum[color]{Val: "green"}, Status: typx.NilFrom(typx.Enum[status]{Val: statusBlocked})})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"color":"green","status":"blocked"}`, string(data))
+
+	_, err = json.Marshal(doc{Color: typx.Enum[color]{Val: "blue"}})
+	assert.Error(t, err)
+
+	var got doc
+	assert.NoError(t, json.Unmarshal([]byte(`{"color":"red","status":null}`), &got))
+	assert.Equal(t, doc{Color: typx.Enum[color]{Val: "red"}}, got)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"color":"blue"}`), &got))
+	assert.Error(t, json.Unmarshal([]byte(`{"color":1}`), &got))
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"color":"red","status":"archived"}`), &got))
+	assert.Equal(t, typx.NilFrom(typx.Enum[status]{Val: statusUnknown}), got.Status)
+}
+
+func Test_Enum_SQL(t *testing.T) {
+	var got typx.Nil[typx.Enum[color]]
+	assert.NoError(t, got.Scan([]byte("green")))
+	assert.Equal(t, typx.NilFrom(typx.Enum[color]{Val: "green"}), got)
+
+	assert.NoError(t, got.Scan(nil))
+	assert.False(t, got.NotNil)
+
+	var c typx.Enum[color]
+	assert.Error(t, c.Scan("blue"))
+	assert.Error(t, c.Scan(int64(1)))
+
+	value, err := typx.Enum[status]{Val: statusActive}.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "active", value)
+
+	_, err = typx.Enum[color]{Val: "blue"}.Value()
+	assert.Error(t, err)
+}
+
+func Test_Enum_BSON(t *testing.T) {
+	type doc struct {
+		Color typx.Enum[color] `bson:"color"`
+	}
+	data, err := bson.Marshal(doc{Color: typx.Enum[color]{Val: "red"}})
+	assert.NoError(t, err)
+
+	var raw bson.M
+	assert.NoError(t, bson.Unmarshal(data, &raw))
+	assert.Equal(t, "red", raw["color"])
+
+	var got doc
+	assert.NoError(t, bson.Unmarshal(data, &got))
+	assert.Equal(t, doc{Color: typx.Enum[color]{Val: "red"}}, got)
+
+	invalid, _ := bson.Marshal(bson.M{"color": "blue"})
+	assert.Error(t, bson.Unmarshal(invalid, &got))
+	invalid, _ = bson.Marshal(bson.M{"color": 1})
+	assert.Error(t, bson.Unmarshal(invalid, &got))
+}
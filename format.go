@@ -0,0 +1,163 @@
+package typx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"unicode/utf8"
+)
+
+// String implements the fmt.Stringer interface, returning "null" or the value.
+func (n Nil[T]) String() string {
+	if !n.NotNil {
+		return "null"
+	}
+	return fmt.Sprint(n.Val)
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (n Nil[T]) GoString() string {
+	if !n.NotNil {
+		return fmt.Sprintf("typx.Nil[%T]{}", n.Val)
+	}
+	return fmt.Sprintf("typx.NilFrom[%T](%#v)", n.Val, n.Val)
+}
+
+// Format implements the fmt.Formatter interface. A nil value is printed as "null",
+// while %+v prints the internal state, e.g. {Val:0 NotNil:false}.
+func (n Nil[T]) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('+'):
+		fmt.Fprintf(f, "{Val:%+v NotNil:%t}", n.Val, n.NotNil)
+	case verb == 'v' && f.Flag('#'):
+		_, _ = io.WriteString(f, n.GoString())
+	case !n.NotNil:
+		_, _ = io.WriteString(f, "null")
+	default:
+		fmt.Fprintf(f, fmt.FormatString(f, verb), n.Val)
+	}
+}
+
+// LogValue implements the slog.LogValuer interface, logging nil values as null.
+func (n Nil[T]) LogValue() slog.Value {
+	if !n.NotNil {
+		return slog.AnyValue(nil)
+	}
+	return slog.AnyValue(n.Val)
+}
+
+// String implements the fmt.Stringer interface, returning "<unset>" or the value.
+func (o Opt[T]) String() string {
+	if !o.Set {
+		return "<unset>"
+	}
+	return fmt.Sprint(o.Val)
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (o Opt[T]) GoString() string {
+	if !o.Set {
+		return fmt.Sprintf("typx.Opt[%T]{}", o.Val)
+	}
+	return fmt.Sprintf("typx.OptFrom[%T](%#v)", o.Val, o.Val)
+}
+
+// Format implements the fmt.Formatter interface. An unset value is printed as "<unset>",
+// while %+v prints the internal state, e.g. {Val:0 Set:false}.
+func (o Opt[T]) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('+'):
+		fmt.Fprintf(f, "{Val:%+v Set:%t}", o.Val, o.Set)
+	case verb == 'v' && f.Flag('#'):
+		_, _ = io.WriteString(f, o.GoString())
+	case !o.Set:
+		_, _ = io.WriteString(f, "<unset>")
+	default:
+		fmt.Fprintf(f, fmt.FormatString(f, verb), o.Val)
+	}
+}
+
+// LogValue implements the slog.LogValuer interface, logging unset values as "<unset>".
+func (o Opt[T]) LogValue() slog.Value {
+	if !o.Set {
+		return slog.StringValue("<unset>")
+	}
+	return slog.AnyValue(o.Val)
+}
+
+// String implements the fmt.Stringer interface, returning compact JSON.
+func (d Dyn) String() string {
+	return d.string(0)
+}
+
+// string returns compact JSON truncated to limit bytes and suffixed with "..." beyond it, unless limit is zero.
+func (d Dyn) string(limit int) string {
+	data, err := json.Marshal(d.Val)
+	if err != nil {
+		return fmt.Sprintf("%v", d.Val)
+	}
+	if limit > 0 && len(data) > limit {
+		end := limit
+		for end > 0 && !utf8.RuneStart(data[end]) {
+			end--
+		}
+		return string(data[:end]) + "..."
+	}
+	return string(data)
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (d Dyn) GoString() string {
+	return fmt.Sprintf("typx.Dyn{Val:%#v}", d.Val)
+}
+
+// Format implements the fmt.Formatter interface, printing the same as String,
+// while %+v prints the internal state, e.g. {Val:map[a:1]}.
+func (d Dyn) Format(f fmt.State, verb rune) {
+	d.format(f, verb, 0)
+}
+
+func (d Dyn) format(f fmt.State, verb rune, limit int) {
+	switch {
+	case verb == 'v' && f.Flag('+'):
+		fmt.Fprintf(f, "{Val:%+v}", d.Val)
+	case verb == 'v' && f.Flag('#'):
+		_, _ = io.WriteString(f, d.GoString())
+	default:
+		fmt.Fprintf(f, fmt.FormatString(f, verb), d.string(limit))
+	}
+}
+
+// LogValue implements the slog.LogValuer interface, logging the same as String.
+func (d Dyn) LogValue() slog.Value {
+	return slog.StringValue(d.String())
+}
+
+// DynLimited is a Dyn whose String, Format and LogValue print at most Limit bytes of compact JSON,
+// which is truncated and suffixed with "..." beyond it. Zero means no limit.
+// It can be used in place of Dyn as a log attribute or a struct field that holds large values.
+type DynLimited struct {
+	Dyn
+	Limit int
+}
+
+// DynLimit returns d printed with at most limit bytes.
+func DynLimit(d Dyn, limit int) DynLimited {
+	return DynLimited{Dyn: d, Limit: limit}
+}
+
+// String implements the fmt.Stringer interface, returning compact JSON capped at Limit.
+func (d DynLimited) String() string {
+	return d.string(d.Limit)
+}
+
+// Format implements the fmt.Formatter interface like Dyn.Format, capped at Limit.
+func (d DynLimited) Format(f fmt.State, verb rune) {
+	d.format(f, verb, d.Limit)
+}
+
+// LogValue implements the slog.LogValuer interface, logging the same as String.
+func (d DynLimited) LogValue() slog.Value {
+	return slog.StringValue(d.String())
+}
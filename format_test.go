@@ -0,0 +1,80 @@
+package typx_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Format(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		value  any
+		want   string
+	}{
+		{name: "nil null", format: "%v", value: typx.Nil[int]{}, want: "null"},
+		{name: "nil value", format: "%v", value: typx.NilFrom(0), want: "0"},
+		{name: "nil verb", format: "%05.1f", value: typx.NilFrom(1.25), want: "001.2"},
+		{name: "nil plus", format: "%+v", value: typx.Nil[int]{}, want: "{Val:0 NotNil:false}"},
+		{name: "nil go", format: "%#v", value: typx.NilFrom("a"), want: `typx.NilFrom[string]("a")`},
+		{name: "nil go null", format: "%#v", value: typx.Nil[int]{}, want: `typx.Nil[int]{}`},
+		{name: "nil string", format: "%s", value: typx.NilFrom("a"), want: "a"},
+		{name: "opt unset", format: "%v", value: typx.Opt[int]{}, want: "<unset>"},
+		{name: "opt value", format: "%v", value: typx.OptFrom(0), want: "0"},
+		{name: "opt nested", format: "%v", value: typx.OptFrom(typx.Nil[int]{}), want: "null"},
+		{name: "opt plus", format: "%+v", value: typx.OptFrom(1), want: "{Val:1 Set:true}"},
+		{name: "opt go", format: "%#v", value: typx.Opt[int]{}, want: `typx.Opt[int]{}`},
+		{name: "dyn", format: "%v", value: typx.Dyn{Val: map[string]any{"a": []any{1, "b"}}}, want: `{"a":[1,"b"]}`},
+		{name: "dyn null", format: "%s", value: typx.Dyn{}, want: "null"},
+		{name: "dyn plus", format: "%+v", value: typx.Dyn{Val: []any{1}}, want: "{Val:[1]}"},
+		{name: "dyn go", format: "%#v", value: typx.Dyn{Val: 1}, want: "typx.Dyn{Val:1}"},
+		{name: "struct", format: "%v", value: struct {
+			A typx.Nil[int]
+			B typx.Opt[string]
+		}{}, want: "{null <unset>}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fmt.Sprintf(tt.format, tt.value))
+		})
+	}
+
+	assert.Equal(t, "null", typx.Nil[int]{}.String())
+	assert.Equal(t, "<unset>", typx.Opt[int]{}.String())
+}
+
+func Test_Format_DynLimit(t *testing.T) {
+	long := typx.Dyn{Val: map[string]any{"a": "bcdefgh"}}
+	assert.Equal(t, `{"a":"bc...`, typx.DynLimit(long, 8).String())
+	assert.Equal(t, `["é","...`, typx.DynLimit(typx.Dyn{Val: []any{"é", "é"}}, 8).String())
+	assert.Equal(t, `[1,2]`, typx.DynLimit(typx.Dyn{Val: []any{1, 2}}, 8).String())
+	assert.Equal(t, `{"a":"bcdefgh"}`, typx.DynLimit(long, 0).String())
+	assert.Equal(t, `{"a":"bcdefgh"}`, long.String())
+	assert.Equal(t, `{"a":"bc...`, fmt.Sprintf("%v", typx.DynLimit(long, 8)))
+	assert.Equal(t, `{Val:map[a:bcdefgh]}`, fmt.Sprintf("%+v", typx.DynLimit(long, 8)))
+	assert.Equal(t, `{"a":"bc...`, typx.DynLimit(long, 8).LogValue().String())
+}
+
+func Test_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+		return a
+	}}))
+	logger.Info("user",
+		"landline", typx.Nil[string]{},
+		"phone", typx.NilFrom("123"),
+		"name", typx.Opt[string]{},
+		"age", typx.OptFrom(30),
+		"info", typx.Dyn{Val: map[string]any{"a": 1}},
+		"key", typx.NilFrom(typx.SecretFrom("sk-123")),
+	)
+	assert.Equal(t, `{"level":"INFO","msg":"user","landline":null,"phone":"123","name":"<unset>","age":30,"info":"{\"a\":1}","key":"[REDACTED]"}`+"\n", buf.String())
+}
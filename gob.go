@@ -0,0 +1,33 @@
+package typx
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+)
+
+// gobEncodeOptional encodes a presence flag followed by the gob encoding of val if present.
+func gobEncodeOptional[T any](present bool, val T) ([]byte, error) {
+	if !present {
+		return []byte{0}, nil
+	}
+	buf := bytes.NewBuffer([]byte{1})
+	if err := gob.NewEncoder(buf).Encode(&val); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gobDecodeOptional decodes data produced by gobEncodeOptional into val and reports whether it was present.
+func gobDecodeOptional[T any](data []byte, val *T) (bool, error) {
+	if len(data) == 0 {
+		return false, errors.New("cannot gob decode: empty data")
+	}
+	if data[0] == 0 {
+		return false, nil
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(val); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,95 @@
+package typx_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+type gobAddress struct {
+	Street string
+	Number int
+}
+
+type gobOther struct{ Street string }
+
+func init() {
+	typx.RegisterDynType[gobAddress]("address")
+}
+
+type gobUser struct {
+	ID       uuid.UUID
+	Landline typx.Nil[string]
+	Mobile   typx.Nil[string]
+	Name     typx.Opt[string]
+	Fax      typx.Opt[typx.Nil[string]]
+	Info     typx.Dyn
+	Address  typx.Dyn
+}
+
+func Test_Gob(t *testing.T) {
+	user := gobUser{
+		ID:       uuid.New(),
+		Landline: typx.NilFrom("123"),
+		Fax:      typx.OptFrom(typx.Nil[string]{}),
+		Info:     typx.Dyn{Val: map[string]any{"n": int64(1), "at": time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)}},
+		Address:  typx.Dyn{Val: gobAddress{Street: "Main", Number: 1}},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(user))
+
+	var got gobUser
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&got))
+	var info typx.Dyn
+	data, err := user.Info.MarshalJSON()
+	assert.NoError(t, err)
+	assert.NoError(t, info.UnmarshalJSON(data))
+	assert.Equal(t, map[string]any{"n": float64(1), "at": "2026-01-15T00:00:00Z"}, info.Val)
+	user.Info = info
+	assert.Equal(t, user, got)
+}
+
+func Test_Dyn_Registered_Binary_Text(t *testing.T) {
+	value := typx.Dyn{Val: gobAddress{Street: "Main", Number: 1}}
+
+	data, err := value.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"$type":"address","$value":{"Street":"Main","Number":1}}`, string(data))
+	got := typx.Dyn{}
+	assert.NoError(t, got.UnmarshalBinary(data))
+	assert.Equal(t, value, got)
+
+	data, err = value.MarshalText()
+	assert.NoError(t, err)
+	got = typx.Dyn{}
+	assert.NoError(t, got.UnmarshalText(data))
+	assert.Equal(t, value, got)
+}
+
+func Test_Dyn_Unregistered_Envelope(t *testing.T) {
+	got := typx.Dyn{}
+	assert.NoError(t, got.UnmarshalText([]byte(`{"$type":"unknown","$value":{"a":[1]}}`)))
+	assert.Equal(t, typx.Dyn{Val: map[string]any{"a": []any{float64(1)}}}, got)
+}
+
+func Test_RegisterDynType_Duplicate(t *testing.T) {
+	assert.NotPanics(t, func() { typx.RegisterDynType[gobAddress]("address") })
+	assert.Panics(t, func() { typx.RegisterDynType[gobOther]("address") })
+	assert.Panics(t, func() { typx.RegisterDynType[gobAddress]("other") })
+}
+
+func Test_Nil_Gob(t *testing.T) {
+	for _, value := range []typx.Nil[int]{{}, typx.NilFrom(0), typx.NilFrom(42)} {
+		data, err := value.GobEncode()
+		assert.NoError(t, err)
+		got := typx.NilFrom(7)
+		assert.NoError(t, got.GobDecode(data))
+		assert.Equal(t, value, got)
+	}
+	assert.Error(t, (&typx.Nil[int]{}).GobDecode(nil))
+}
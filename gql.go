@@ -0,0 +1,125 @@
+package typx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// gqlMarshaler and gqlUnmarshaler are the marshaling conventions of gqlgen for custom scalars.
+type gqlMarshaler interface{ MarshalGQL(w io.Writer) }
+
+type gqlUnmarshaler interface{ UnmarshalGQL(v any) error }
+
+var (
+	_ gqlMarshaler   = Nil[int]{}
+	_ gqlUnmarshaler = (*Nil[int])(nil)
+	_ gqlMarshaler   = Opt[int]{}
+	_ gqlUnmarshaler = (*Opt[int])(nil)
+	_ gqlMarshaler   = Dyn{}
+	_ gqlUnmarshaler = (*Dyn)(nil)
+)
+
+// MarshalGQL implements the graphql.Marshaler interface of gqlgen.
+// A nil value is written as null, and other values as their MarshalGQL or JSON encoding.
+func (n Nil[T]) MarshalGQL(w io.Writer) {
+	if !n.NotNil {
+		_, _ = io.WriteString(w, "null")
+		return
+	}
+	marshalGQLValue(w, n.Val)
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface of gqlgen.
+// A null input makes the value nil.
+func (n *Nil[T]) UnmarshalGQL(v any) error {
+	*n = Nil[T]{}
+	if v == nil {
+		return nil
+	}
+	if err := unmarshalGQLValue(v, &n.Val); err != nil {
+		return fmt.Errorf("cannot unmarshal GraphQL %T into Nil[%T]: %w", v, n.Val, err)
+	}
+	n.NotNil = true
+	return nil
+}
+
+// MarshalGQL implements the graphql.Marshaler interface of gqlgen.
+// An unset value is written as null, since GraphQL responses cannot omit fields.
+func (o Opt[T]) MarshalGQL(w io.Writer) {
+	if !o.Set {
+		_, _ = io.WriteString(w, "null")
+		return
+	}
+	marshalGQLValue(w, o.Val)
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface of gqlgen.
+// gqlgen only calls it for the fields of an input object that are provided, so that omitted fields are left unset,
+// while provided ones are set even if they are null. Use Opt[Nil[T]] and IsExplicitNull to tell null apart.
+func (o *Opt[T]) UnmarshalGQL(v any) error {
+	*o = Opt[T]{}
+	if err := unmarshalGQLValue(v, &o.Val); err != nil {
+		return fmt.Errorf("cannot unmarshal GraphQL %T into Opt[%T]: %w", v, o.Val, err)
+	}
+	o.Set = true
+	return nil
+}
+
+// IsExplicitNull reports whether an input field was provided as null, rather than omitted or provided with a value.
+func IsExplicitNull[T any](o Opt[Nil[T]]) bool {
+	return o.Set && !o.Val.NotNil
+}
+
+// MarshalGQL implements the graphql.Marshaler interface of gqlgen, writing the value as a JSON scalar.
+func (d Dyn) MarshalGQL(w io.Writer) {
+	marshalGQLValue(w, d.Val)
+}
+
+// UnmarshalGQL implements the graphql.Unmarshaler interface of gqlgen, reading a JSON scalar.
+// The input is normalized the same way as by UnmarshalJSON, so that numbers are float64 values.
+func (d *Dyn) UnmarshalGQL(v any) error {
+	d.Val = nil
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal GraphQL %T into Dyn: %w", v, err)
+	}
+	return json.Unmarshal(data, &d.Val)
+}
+
+// marshalGQLValue writes v with its MarshalGQL method, or as JSON otherwise.
+// Since MarshalGQL cannot return an error, values that cannot be encoded are written as null.
+func marshalGQLValue(w io.Writer, v any) {
+	if m, ok := v.(gqlMarshaler); ok {
+		m.MarshalGQL(w)
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		data = []byte("null")
+	}
+	_, _ = w.Write(data)
+}
+
+// unmarshalGQLValue decodes a gqlgen input value, which is shaped like decoded JSON, into dst.
+func unmarshalGQLValue(v, dst any) error {
+	if u, ok := dst.(gqlUnmarshaler); ok {
+		return u.UnmarshalGQL(v)
+	}
+	if v == nil {
+		return nil
+	}
+	if val, target := reflect.ValueOf(v), reflect.ValueOf(dst).Elem(); val.Type().AssignableTo(target.Type()) {
+		target.Set(val)
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
@@ -0,0 +1,99 @@
+package typx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Nil_GQL(t *testing.T) {
+	var b bytes.Buffer
+	typx.NilFrom("a\"b").MarshalGQL(&b)
+	assert.Equal(t, `"a\"b"`, b.String())
+	b.Reset()
+	typx.Nil[int]{}.MarshalGQL(&b)
+	assert.Equal(t, "null", b.String())
+	b.Reset()
+	typx.NilFrom(typx.Dyn{Val: map[string]any{"a": 1}}).MarshalGQL(&b)
+	assert.Equal(t, `{"a":1}`, b.String())
+
+	var n typx.Nil[int64]
+	require.NoError(t, n.UnmarshalGQL(int64(5)))
+	assert.Equal(t, typx.NilFrom(int64(5)), n)
+	require.NoError(t, n.UnmarshalGQL(json.Number("6")))
+	assert.Equal(t, typx.NilFrom(int64(6)), n)
+	require.NoError(t, n.UnmarshalGQL(nil))
+	assert.Equal(t, typx.Nil[int64]{}, n)
+	assert.Error(t, n.UnmarshalGQL("x"))
+
+	var ts typx.Nil[time.Time]
+	require.NoError(t, ts.UnmarshalGQL("2024-01-02T03:04:05Z"))
+	assert.Equal(t, typx.NilFrom(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), ts)
+}
+
+// gqlInput mimics how gqlgen unmarshals input objects, calling UnmarshalGQL only for the provided fields.
+type gqlInput struct {
+	Name  typx.Opt[string]
+	Phone typx.Opt[typx.Nil[string]]
+}
+
+func (in *gqlInput) unmarshal(t *testing.T, fields map[string]any) {
+	*in = gqlInput{}
+	for key, v := range fields {
+		switch key {
+		case "name":
+			require.NoError(t, in.Name.UnmarshalGQL(v))
+		case "phone":
+			require.NoError(t, in.Phone.UnmarshalGQL(v))
+		}
+	}
+}
+
+func Test_Opt_GQL(t *testing.T) {
+	var in gqlInput
+	in.unmarshal(t, map[string]any{})
+	assert.False(t, in.Name.Set)
+	assert.False(t, in.Phone.Set)
+	assert.False(t, typx.IsExplicitNull(in.Phone))
+
+	in.unmarshal(t, map[string]any{"name": "a", "phone": nil})
+	assert.Equal(t, typx.OptFrom("a"), in.Name)
+	assert.Equal(t, typx.OptFrom(typx.Nil[string]{}), in.Phone)
+	assert.True(t, typx.IsExplicitNull(in.Phone))
+
+	in.unmarshal(t, map[string]any{"phone": "123"})
+	assert.Equal(t, typx.OptFrom(typx.NilFrom("123")), in.Phone)
+	assert.False(t, typx.IsExplicitNull(in.Phone))
+
+	var o typx.Opt[int]
+	assert.Error(t, o.UnmarshalGQL([]any{1}))
+	assert.False(t, o.Set)
+
+	var b bytes.Buffer
+	typx.OptFrom([]int{1, 2}).MarshalGQL(&b)
+	assert.Equal(t, "[1,2]", b.String())
+	b.Reset()
+	typx.Opt[int]{}.MarshalGQL(&b)
+	assert.Equal(t, "null", b.String())
+}
+
+func Test_Dyn_GQL(t *testing.T) {
+	var d typx.Dyn
+	require.NoError(t, d.UnmarshalGQL(map[string]any{"a": int64(1), "b": []any{json.Number("2.5"), true}}))
+	assert.Equal(t, map[string]any{"a": 1.0, "b": []any{2.5, true}}, d.Val)
+	require.NoError(t, d.UnmarshalGQL(nil))
+	assert.Nil(t, d.Val)
+	assert.Error(t, d.UnmarshalGQL(make(chan int)))
+
+	var b bytes.Buffer
+	typx.Dyn{Val: map[string]any{"a": []int{1}}}.MarshalGQL(&b)
+	assert.Equal(t, `{"a":[1]}`, b.String())
+	b.Reset()
+	typx.Dyn{Val: make(chan int)}.MarshalGQL(&b)
+	assert.Equal(t, "null", b.String())
+}
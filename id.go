@@ -0,0 +1,226 @@
+package typx
+
+import (
+	"database/sql/driver"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// ID is a UUID identifying an entity of type E, so that e.g. an ID[User] cannot be passed as an ID[Order].
+// E only serves as a marker and may implement IDPrefixer and IDEncoder to customize the string form,
+// e.g. "usr_01h455vb4pex5vsknk084sn02q" instead of the canonical UUID form.
+// It implements interfaces for SQL (as a uuid), JSON, text and BSON (as binary subtype 4) encoding.
+type ID[E any] uuid.UUID
+
+// IDPrefixer is implemented by entity types whose IDs have a prefix, which is separated from the encoded UUID by '_'.
+type IDPrefixer interface {
+	IDPrefix() string
+}
+
+// IDEncoder is implemented by entity types whose IDs use an encoding other than IDEncodingUUID.
+type IDEncoder interface {
+	IDEncoding() IDEncoding
+}
+
+// IDEncoding is the encoding of the UUID in the string form of an ID.
+type IDEncoding int
+
+const (
+	// IDEncodingUUID is the canonical UUID form, e.g. 0188e5c2-...
+	IDEncodingUUID IDEncoding = iota
+	// IDEncodingBase32 is the lowercase Crockford base32 form with 26 characters.
+	IDEncodingBase32
+	// IDEncodingBase58 is the Bitcoin base58 form with 22 characters.
+	IDEncodingBase58
+)
+
+var idBase32 = base32.NewEncoding("0123456789abcdefghjkmnpqrstvwxyz").WithPadding(base32.NoPadding)
+
+const idBase58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// NewID returns a new time-ordered ID using a version 7 UUID. Like uuid.New, it panics if generation fails.
+func NewID[E any]() ID[E] {
+	return ID[E](uuid.Must(uuid.NewV7()))
+}
+
+// IDFrom returns the ID of the given UUID.
+func IDFrom[E any](u uuid.UUID) ID[E] {
+	return ID[E](u)
+}
+
+// ParseID parses the string form of an ID. The prefix of E is required if it has one,
+// and the canonical UUID form is accepted regardless of the encoding of E.
+func ParseID[E any](s string) (ID[E], error) {
+	prefix, encoding := idFormat[E]()
+	if prefix != "" {
+		rest, ok := strings.CutPrefix(s, prefix+"_")
+		if !ok {
+			return ID[E]{}, fmt.Errorf("cannot parse ID %q: expected the prefix %q", s, prefix+"_")
+		}
+		s = rest
+	}
+	switch {
+	case encoding == IDEncodingBase32 && len(s) == 26:
+		data, err := idBase32.DecodeString(strings.ToLower(s))
+		if err != nil || len(data) != 16 {
+			return ID[E]{}, fmt.Errorf("cannot parse ID %q: invalid base32", s)
+		}
+		return ID[E](data), nil
+	case encoding == IDEncodingBase58 && len(s) <= 22 && len(s) > 0:
+		n := new(big.Int)
+		for _, c := range []byte(s) {
+			digit := strings.IndexByte(idBase58Alphabet, c)
+			if digit < 0 {
+				return ID[E]{}, fmt.Errorf("cannot parse ID %q: invalid base58", s)
+			}
+			n.Mul(n, big.NewInt(58)).Add(n, big.NewInt(int64(digit)))
+		}
+		if n.BitLen() > 128 {
+			return ID[E]{}, fmt.Errorf("cannot parse ID %q: invalid base58", s)
+		}
+		var id ID[E]
+		n.FillBytes(id[:])
+		return id, nil
+	}
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return ID[E]{}, fmt.Errorf("cannot parse ID %q: %w", s, err)
+	}
+	return ID[E](u), nil
+}
+
+func idFormat[E any]() (string, IDEncoding) {
+	var e E
+	var prefix string
+	encoding := IDEncodingUUID
+	if p, ok := any(e).(IDPrefixer); ok {
+		prefix = p.IDPrefix()
+	}
+	if enc, ok := any(e).(IDEncoder); ok {
+		encoding = enc.IDEncoding()
+	}
+	return prefix, encoding
+}
+
+// UUID returns the underlying UUID.
+func (id ID[E]) UUID() uuid.UUID { return uuid.UUID(id) }
+
+// IsZero reports whether the ID is the nil UUID.
+func (id ID[E]) IsZero() bool { return id == ID[E]{} }
+
+// String returns the string form of the ID, using the prefix and encoding of E.
+func (id ID[E]) String() string {
+	prefix, encoding := idFormat[E]()
+	var s string
+	switch encoding {
+	case IDEncodingBase32:
+		s = idBase32.EncodeToString(id[:])
+	case IDEncodingBase58:
+		var b [22]byte
+		n := new(big.Int).SetBytes(id[:])
+		mod := new(big.Int)
+		base := big.NewInt(58)
+		for i := len(b) - 1; i >= 0; i-- {
+			n.DivMod(n, base, mod)
+			b[i] = idBase58Alphabet[mod.Int64()]
+		}
+		s = string(b[:])
+	default:
+		s = uuid.UUID(id).String()
+	}
+	if prefix != "" {
+		return prefix + "_" + s
+	}
+	return s
+}
+
+// Scan implements the sql.Scanner interface for uuid columns (the canonical UUID form without a prefix),
+// raw 16 byte values and string forms.
+func (id *ID[E]) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		if len(v) == 16 {
+			copy(id[:], v)
+			return nil
+		}
+		return id.Scan(string(v))
+	case string:
+		if u, err := uuid.Parse(v); err == nil {
+			*id = ID[E](u)
+			return nil
+		}
+		return id.UnmarshalText([]byte(v))
+	}
+	return fmt.Errorf("cannot scan %T into ID: expected a UUID ([]byte or string)", src)
+}
+
+// Value implements the driver.Valuer interface, returning the canonical UUID form for uuid columns.
+func (id ID[E]) Value() (driver.Value, error) {
+	return uuid.UUID(id).String(), nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (id ID[E]) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (id *ID[E]) UnmarshalText(text []byte) error {
+	parsed, err := ParseID[E](string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (id ID[E]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(id.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Like other decoders, it ignores null.
+func (id *ID[E]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("cannot unmarshal %s into ID: expected a string", data)
+	}
+	return id.UnmarshalText([]byte(s))
+}
+
+// MarshalBSONValue implements the bson.ValueMarshaler interface, encoding the ID as binary subtype 4.
+func (id ID[E]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.TypeBinary, bsoncore.AppendBinary(nil, bson.TypeBinaryUUID, id[:]), nil
+}
+
+// UnmarshalBSONValue implements the bson.ValueUnmarshaler interface for binary subtype 4 and string forms.
+func (id *ID[E]) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	switch t {
+	case bson.TypeBinary:
+		subtype, bin, _, ok := bsoncore.ReadBinary(data)
+		if !ok || subtype != bson.TypeBinaryUUID || len(bin) != 16 {
+			return errors.New("cannot unmarshal BSON binary into ID: expected a UUID (subtype 4)")
+		}
+		copy(id[:], bin)
+		return nil
+	case bson.TypeString:
+		s, _, ok := bsoncore.ReadString(data)
+		if !ok {
+			return errors.New("cannot unmarshal BSON string into ID: invalid data")
+		}
+		return id.UnmarshalText([]byte(s))
+	}
+	return fmt.Errorf("cannot unmarshal BSON %s into ID: expected a binary UUID or a string", t)
+}
@@ -0,0 +1,132 @@
+package typx_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type idUser struct{}
+
+func (idUser) IDPrefix() string            { return "usr" }
+func (idUser) IDEncoding() typx.IDEncoding { return typx.IDEncodingBase32 }
+
+type idOrder struct{}
+
+func (idOrder) IDPrefix() string            { return "ord" }
+func (idOrder) IDEncoding() typx.IDEncoding { return typx.IDEncodingBase58 }
+
+type idPlain struct{}
+
+var idFixture = uuid.MustParse("0188e5c2-9a3b-7c4d-8e5f-6a7b8c9d0e1f")
+
+func Test_ID_String(t *testing.T) {
+	user := typx.IDFrom[idUser](idFixture)
+	order := typx.IDFrom[idOrder](idFixture)
+	plain := typx.IDFrom[idPlain](idFixture)
+
+	assert.Regexp(t, `^usr_[0-9a-hjkmnp-tv-z]{26}$`, user.String())
+	assert.Regexp(t, `^ord_[1-9A-HJ-NP-Za-km-z]{22}$`, order.String())
+	assert.Equal(t, idFixture.String(), plain.String())
+
+	for _, s := range []string{user.String(), "usr_" + idFixture.String()} {
+		got, err := typx.ParseID[idUser](s)
+		assert.NoError(t, err)
+		assert.Equal(t, user, got)
+	}
+	got, err := typx.ParseID[idOrder](order.String())
+	assert.NoError(t, err)
+	assert.Equal(t, order, got)
+
+	zero, err := typx.ParseID[idOrder]("ord_1111111111111111111111")
+	assert.NoError(t, err)
+	assert.True(t, zero.IsZero())
+
+	for _, invalid := range []string{idFixture.String(), "ord_" + idFixture.String()[:8], "usr_" + "iiiiiiiiiiiiiiiiiiiiiiiiii"} {
+		_, err := typx.ParseID[idUser](invalid)
+		assert.Error(t, err, invalid)
+	}
+	_, err = typx.ParseID[idOrder]("ord_zzzzzzzzzzzzzzzzzzzzzz")
+	assert.Error(t, err)
+}
+
+func Test_ID_New(t *testing.T) {
+	a, b := typx.NewID[idUser](), typx.NewID[idUser]()
+	assert.Equal(t, uuid.Version(7), a.UUID().Version())
+	assert.NotEqual(t, a, b)
+	assert.Less(t, a.String(), b.String())
+}
+
+func Test_ID_SQL(t *testing.T) {
+	var got typx.Nil[typx.ID[idUser]]
+	assert.NoError(t, got.Scan(idFixture.String()))
+	assert.Equal(t, typx.NilFrom(typx.IDFrom[idUser](idFixture)), got)
+
+	assert.NoError(t, got.Scan(idFixture[:]))
+	assert.Equal(t, typx.NilFrom(typx.IDFrom[idUser](idFixture)), got)
+
+	assert.NoError(t, got.Scan(nil))
+	assert.False(t, got.NotNil)
+
+	value, err := typx.NilFrom(typx.IDFrom[idUser](idFixture)).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, idFixture.String(), value)
+
+	var id typx.ID[idUser]
+	assert.Error(t, id.Scan(1))
+	assert.Error(t, id.Scan("abc"))
+}
+
+func Test_ID_JSON(t *testing.T) {
+	type doc struct {
+		ID     typx.ID[idUser]           `json:"id"`
+		Parent typx.Nil[typx.ID[idUser]] `json:"parent"`
+	}
+	user := typx.IDFrom[idUser](idFixture)
+	data, err := json.Marshal(doc{ID: user})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"`+user.String()+`","parent":null}`, string(data))
+
+	var got doc
+	assert.NoError(t, json.Unmarshal([]byte(`{"id":"`+user.String()+`","parent":"`+user.String()+`"}`), &got))
+	assert.Equal(t, doc{ID: user, Parent: typx.NilFrom(user)}, got)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"id":1}`), &got))
+	assert.Error(t, json.Unmarshal([]byte(`{"id":"ord_1"}`), &got))
+}
+
+func Test_ID_BSON(t *testing.T) {
+	type doc struct {
+		ID     typx.ID[idUser]           `bson:"id"`
+		Parent typx.Nil[typx.ID[idUser]] `bson:"parent"`
+	}
+	user := typx.IDFrom[idUser](idFixture)
+	for _, value := range []doc{{ID: user}, {ID: user, Parent: typx.NilFrom(user)}} {
+		data, err := bson.Marshal(value)
+		assert.NoError(t, err)
+
+		var raw bson.Raw = data
+		subtype, bin := raw.Lookup("id").Binary()
+		assert.Equal(t, bson.TypeBinaryUUID, subtype)
+		assert.Equal(t, idFixture[:], bin)
+		if !value.Parent.NotNil {
+			assert.Equal(t, bson.TypeNull, raw.Lookup("parent").Type)
+		}
+
+		var got doc
+		assert.NoError(t, bson.Unmarshal(data, &got))
+		assert.Equal(t, value, got)
+	}
+
+	var got doc
+	data, _ := bson.Marshal(bson.M{"id": user.String()})
+	assert.NoError(t, bson.Unmarshal(data, &got))
+	assert.Equal(t, user, got.ID)
+
+	data, _ = bson.Marshal(bson.M{"id": 1})
+	assert.Error(t, bson.Unmarshal(data, &got))
+}
@@ -0,0 +1,122 @@
+package typx
+
+import "iter"
+
+// Values returns the values of the non-nil elements of seq, skipping nil ones.
+func Values[T any](seq iter.Seq[Nil[T]]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := range seq {
+			if n.NotNil && !yield(n.Val) {
+				return
+			}
+		}
+	}
+}
+
+// OptValues returns the values of the set elements of seq, skipping unset ones.
+func OptValues[T any](seq iter.Seq[Opt[T]]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for o := range seq {
+			if o.Set && !yield(o.Val) {
+				return
+			}
+		}
+	}
+}
+
+// Compact returns the values of the non-nil elements of s, skipping nil ones.
+func Compact[T any](s []Nil[T]) []T {
+	result := make([]T, 0, len(s))
+	for _, n := range s {
+		if n.NotNil {
+			result = append(result, n.Val)
+		}
+	}
+	return result
+}
+
+// CountNull returns the number of nil elements of seq.
+func CountNull[T any](seq iter.Seq[Nil[T]]) int {
+	count := 0
+	for n := range seq {
+		if !n.NotNil {
+			count++
+		}
+	}
+	return count
+}
+
+// MapNils returns the elements of seq with f applied to the non-nil values, keeping nil ones nil.
+func MapNils[T, U any](seq iter.Seq[Nil[T]], f func(T) U) iter.Seq[Nil[U]] {
+	return func(yield func(Nil[U]) bool) {
+		for n := range seq {
+			var mapped Nil[U]
+			if n.NotNil {
+				mapped = NilFrom(f(n.Val))
+			}
+			if !yield(mapped) {
+				return
+			}
+		}
+	}
+}
+
+// Collect collects the values of seq into a slice, which is nil if seq is empty like array_agg in SQL.
+func Collect[T any](seq iter.Seq[T]) Nil[[]T] {
+	var result []T
+	for v := range seq {
+		result = append(result, v)
+	}
+	if result == nil {
+		return Nil[[]T]{}
+	}
+	return NilFrom(result)
+}
+
+// NilsToPtrs converts s to pointers, where nil elements become nil pointers.
+func NilsToPtrs[T any](s []Nil[T]) []*T {
+	if s == nil {
+		return nil
+	}
+	result := make([]*T, len(s))
+	for i, n := range s {
+		result[i] = n.Ptr()
+	}
+	return result
+}
+
+// PtrsToNils converts s from pointers, where nil pointers become nil elements.
+func PtrsToNils[T any](s []*T) []Nil[T] {
+	if s == nil {
+		return nil
+	}
+	result := make([]Nil[T], len(s))
+	for i, p := range s {
+		result[i] = NilFromPtr(p)
+	}
+	return result
+}
+
+// NilMapToPtrs converts the values of m to pointers, where nil values become nil pointers.
+func NilMapToPtrs[K comparable, T any](m map[K]Nil[T]) map[K]*T {
+	if m == nil {
+		return nil
+	}
+	result := make(map[K]*T, len(m))
+	for k, n := range m {
+		result[k] = n.Ptr()
+	}
+	return result
+}
+
+// PtrMapToNils converts the values of m from pointers, where nil pointers become nil values.
+func PtrMapToNils[K comparable, T any](m map[K]*T) map[K]Nil[T] {
+	if m == nil {
+		return nil
+	}
+	result := make(map[K]Nil[T], len(m))
+	for k, p := range m {
+		result[k] = NilFromPtr(p)
+	}
+	return result
+}
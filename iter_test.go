@@ -0,0 +1,59 @@
+package typx_test
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Iter(t *testing.T) {
+	s := []typx.Nil[int]{typx.NilFrom(1), {}, typx.NilFrom(0), {}, typx.NilFrom(3)}
+
+	assert.Equal(t, []int{1, 0, 3}, slices.Collect(typx.Values(slices.Values(s))))
+	assert.Equal(t, []int{1, 0, 3}, typx.Compact(s))
+	assert.Equal(t, []int{}, typx.Compact([]typx.Nil[int]{{}}))
+	assert.Equal(t, 2, typx.CountNull(slices.Values(s)))
+
+	mapped := slices.Collect(typx.MapNils(slices.Values(s), strconv.Itoa))
+	assert.Equal(t, []typx.Nil[string]{typx.NilFrom("1"), {}, typx.NilFrom("0"), {}, typx.NilFrom("3")}, mapped)
+
+	opts := []typx.Opt[string]{typx.OptFrom("a"), {}, typx.OptFrom("")}
+	assert.Equal(t, []string{"a", ""}, slices.Collect(typx.OptValues(slices.Values(opts))))
+
+	for v := range typx.Values(slices.Values(s)) {
+		assert.Equal(t, 1, v)
+		break
+	}
+	for v := range typx.MapNils(slices.Values(s), strconv.Itoa) {
+		assert.Equal(t, typx.NilFrom("1"), v)
+		break
+	}
+}
+
+func Test_Collect(t *testing.T) {
+	assert.Equal(t, typx.NilFrom([]int{1, 3}), typx.Collect(typx.Values(slices.Values([]typx.Nil[int]{typx.NilFrom(1), {}, typx.NilFrom(3)}))))
+	assert.Equal(t, typx.Nil[[]int]{}, typx.Collect(slices.Values([]int{})))
+}
+
+func Test_NilsToPtrs(t *testing.T) {
+	one := 1
+	nils := []typx.Nil[int]{typx.NilFrom(1), {}}
+	ptrs := typx.NilsToPtrs(nils)
+	assert.Equal(t, []*int{&one, nil}, ptrs)
+	assert.Equal(t, nils, typx.PtrsToNils(ptrs))
+	assert.Nil(t, typx.NilsToPtrs[int](nil))
+	assert.Nil(t, typx.PtrsToNils[int](nil))
+
+	nilMap := map[string]typx.Nil[int]{"a": typx.NilFrom(1), "b": {}}
+	ptrMap := typx.NilMapToPtrs(nilMap)
+	assert.Equal(t, map[string]*int{"a": &one, "b": nil}, ptrMap)
+	assert.Equal(t, nilMap, typx.PtrMapToNils(ptrMap))
+	assert.Nil(t, typx.NilMapToPtrs[string, int](nil))
+	assert.Nil(t, typx.PtrMapToNils[string, int](nil))
+
+	assert.Equal(t, 1, typx.CountNull(maps.Values(nilMap)))
+}
@@ -0,0 +1,73 @@
+//go:build goexperiment.jsonv2
+
+package typx
+
+import "errors"
+
+// The following implementations are only available with the jsonv2 experiment
+// (GOEXPERIMENT=jsonv2 since Go 1.25, enabled by default since Go 1.27).
+// They let encoding/json/v2 (and encoding/json, which is backed by it under the experiment)
+// stream values directly instead of allocating intermediate byte slices.
+
+// MarshalJSONTo implements the json.MarshalerTo interface.
+func (n Nil[T]) MarshalJSONTo(enc *jsonEncoder) error {
+	if !n.NotNil {
+		return enc.WriteToken(jsonNull)
+	}
+	return jsonMarshalEncode(enc, n.Val)
+}
+
+// UnmarshalJSONFrom implements the json.UnmarshalerFrom interface.
+// Like Nil.UnmarshalJSON, it replaces the previous value instead of merging into it.
+func (n *Nil[T]) UnmarshalJSONFrom(dec *jsonDecoder) error {
+	n.NotNil = false
+	n.Val = *new(T)
+	if dec.PeekKind() == 'n' {
+		_, err := dec.ReadToken()
+		return err
+	}
+	if err := jsonUnmarshalDecode(dec, &n.Val); err != nil {
+		return err
+	}
+	n.NotNil = true
+	return nil
+}
+
+// MarshalJSONTo implements the json.MarshalerTo interface.
+// The value is encoded as is, and an unset value is encoded as null.
+// Use the omitzero tag option to omit unset values, which relies on Opt.IsZero.
+// When called by encoding/json (v1), the existing {"val":...,"set":...} representation is kept.
+func (o Opt[T]) MarshalJSONTo(enc *jsonEncoder) error {
+	if isJSONv1(enc.Options()) {
+		return errors.ErrUnsupported
+	}
+	if !o.Set {
+		return enc.WriteToken(jsonNull)
+	}
+	return jsonMarshalEncode(enc, o.Val)
+}
+
+// UnmarshalJSONFrom implements the json.UnmarshalerFrom interface.
+// Any present value (including null) marks the Opt as set, while absent members leave it unset.
+// When called by encoding/json (v1), the existing {"val":...,"set":...} representation is kept.
+func (o *Opt[T]) UnmarshalJSONFrom(dec *jsonDecoder) error {
+	if isJSONv1(dec.Options()) {
+		return errors.ErrUnsupported
+	}
+	o.Set = false
+	if err := jsonUnmarshalDecode(dec, &o.Val); err != nil {
+		return err
+	}
+	o.Set = true
+	return nil
+}
+
+// MarshalJSONTo implements the json.MarshalerTo interface.
+func (d Dyn) MarshalJSONTo(enc *jsonEncoder) error {
+	return jsonMarshalEncode(enc, d.Val)
+}
+
+// UnmarshalJSONFrom implements the json.UnmarshalerFrom interface.
+func (d *Dyn) UnmarshalJSONFrom(dec *jsonDecoder) error {
+	return jsonUnmarshalDecode(dec, &d.Val)
+}
@@ -0,0 +1,33 @@
+//go:build goexperiment.jsonv2 && go1.27
+
+package typx
+
+import (
+	"encoding/json"
+	"encoding/json/jsontext"
+	jsonv2 "encoding/json/v2"
+)
+
+// The encoding/json/v2 API used by json_v2.go, which is declared here once per toolchain,
+// since it is experimental before Go 1.27 and vet only accepts it in files built for Go 1.27 and later.
+
+type (
+	jsonEncoder = jsontext.Encoder
+	jsonDecoder = jsontext.Decoder
+)
+
+var jsonNull = jsontext.Null
+
+func jsonMarshalEncode(enc *jsonEncoder, v any) error {
+	return jsonv2.MarshalEncode(enc, v)
+}
+
+func jsonUnmarshalDecode(dec *jsonDecoder, v any) error {
+	return jsonv2.UnmarshalDecode(dec, v)
+}
+
+// isJSONv1 reports whether the options are the legacy ones used by encoding/json (v1).
+func isJSONv1(opts jsonv2.Options) bool {
+	v1, _ := jsonv2.GetOption(opts, json.OmitEmptyWithLegacySemantics)
+	return v1
+}
@@ -0,0 +1,131 @@
+//go:build goexperiment.jsonv2 && go1.27
+
+package typx_test
+
+import (
+	"encoding/json"
+	jsonv2 "encoding/json/v2"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonV2User struct {
+	Name     typx.Opt[string]           `json:"name,omitzero"`
+	Landline typx.Nil[string]           `json:"landline"`
+	Fax      typx.Opt[typx.Nil[string]] `json:"fax,omitzero"`
+	Info     typx.Dyn                   `json:"info"`
+}
+
+func Test_JSONv2_Marshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value jsonV2User
+		want  string
+	}{
+		{
+			name:  "empty",
+			value: jsonV2User{},
+			want:  `{"landline":null,"info":null}`,
+		},
+		{
+			name: "full",
+			value: jsonV2User{
+				Name:     typx.OptFrom("name"),
+				Landline: typx.NilFrom("123"),
+				Fax:      typx.OptFrom(typx.Nil[string]{}),
+				Info:     typx.Dyn{Val: map[string]any{"a": []any{1, "b"}}},
+			},
+			want: `{"name":"name","landline":"123","fax":null,"info":{"a":[1,"b"]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonv2.Marshal(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_JSONv2_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  jsonV2User
+	}{
+		{
+			name:  "absent",
+			value: `{}`,
+			want:  jsonV2User{},
+		},
+		{
+			name:  "null",
+			value: `{"name":null,"landline":null,"fax":null,"info":null}`,
+			want:  jsonV2User{Name: typx.OptFrom(""), Fax: typx.OptFrom(typx.Nil[string]{})},
+		},
+		{
+			name:  "values",
+			value: `{"name":"name","landline":"123","fax":"456","info":{"a":[1,"b"]}}`,
+			want: jsonV2User{
+				Name:     typx.OptFrom("name"),
+				Landline: typx.NilFrom("123"),
+				Fax:      typx.OptFrom(typx.NilFrom("456")),
+				Info:     typx.Dyn{Val: map[string]any{"a": []any{float64(1), "b"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got jsonV2User
+			assert.NoError(t, jsonv2.Unmarshal([]byte(tt.value), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	var invalid typx.Nil[int]
+	assert.Error(t, jsonv2.Unmarshal([]byte(`"abc"`), &invalid))
+	assert.False(t, invalid.NotNil)
+}
+
+func Test_JSONv2_KeepsV1Behavior(t *testing.T) {
+	value := struct {
+		Name     typx.Opt[string]
+		Landline typx.Nil[string]
+	}{Name: typx.OptFrom("name")}
+	got, err := json.Marshal(value)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Name":{"val":"name","set":true},"Landline":null}`, string(got))
+
+	value.Name = typx.Opt[string]{}
+	assert.NoError(t, json.Unmarshal(got, &value))
+	assert.Equal(t, typx.OptFrom("name"), value.Name)
+}
+
+func Test_JSONv2_MatchesSchemaFor(t *testing.T) {
+	s, err := typx.SchemaFor[jsonV2User]()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"landline", "info"}, s.Required)
+
+	data, err := jsonv2.Marshal(jsonV2User{
+		Name: typx.OptFrom("name"),
+		Fax:  typx.OptFrom(typx.NilFrom("123")),
+		Info: typx.Dyn{Val: 1},
+	})
+	assert.NoError(t, err)
+	var object map[string]any
+	assert.NoError(t, json.Unmarshal(data, &object))
+	assert.Equal(t, map[string]any{"name": "name", "landline": nil, "fax": "123", "info": float64(1)}, object)
+	assert.ElementsMatch(t, slices.Collect(maps.Keys(s.Properties)), slices.Collect(maps.Keys(object)))
+	assert.Equal(t, typx.SchemaType{"string"}, s.Properties["name"].Type)
+	assert.Equal(t, typx.SchemaType{"string", "null"}, s.Properties["landline"].Type)
+	assert.Equal(t, typx.SchemaType{"string", "null"}, s.Properties["fax"].Type)
+
+	// encoding/json (v1) encodes Opt as {"val":...,"set":...}, which the schema does not describe.
+	data, err = json.Marshal(jsonV2User{Name: typx.OptFrom("name")})
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"name":{"val":"name","set":true}`)
+}
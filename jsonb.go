@@ -0,0 +1,175 @@
+package typx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SQLFragment is a parameterized SQL fragment.
+// Placeholders are numbered from $1 and can be shifted with At when the fragment is embedded in a larger query.
+type SQLFragment struct {
+	SQL  string
+	Args []any
+}
+
+// At returns the SQL with its placeholders renumbered to start after the given number of preceding arguments.
+func (f SQLFragment) At(offset int) string {
+	if offset == 0 {
+		return f.SQL
+	}
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(f.SQL); i++ {
+		c := f.SQL[i]
+		if c == '"' {
+			quoted = !quoted
+		}
+		if c != '$' || quoted {
+			b.WriteByte(c)
+			continue
+		}
+		j := i + 1
+		for j < len(f.SQL) && f.SQL[j] >= '0' && f.SQL[j] <= '9' {
+			j++
+		}
+		n, err := strconv.Atoi(f.SQL[i+1 : j])
+		if err != nil {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString("$" + strconv.Itoa(n+offset))
+		i = j - 1
+	}
+	return b.String()
+}
+
+// And combines both fragments with AND, renumbering the placeholders of other.
+func (f SQLFragment) And(other SQLFragment) SQLFragment {
+	return SQLFragment{
+		SQL:  "(" + f.SQL + ") AND (" + other.At(len(f.Args)) + ")",
+		Args: append(append([]any(nil), f.Args...), other.Args...),
+	}
+}
+
+// JSONBExpr is a PostgreSQL JSONB value addressed by a column and a path.
+// It builds parameterized fragments where path segments and compared values are passed as arguments,
+// so they are never interpolated into the SQL.
+type JSONBExpr struct {
+	column   string
+	pointer  []string
+	jsonPath string
+	err      error
+}
+
+// JSONBPath addresses the JSONB value of column at path, which is either an RFC 6901 JSON Pointer
+// (e.g. "/a/b", or "" for the whole column) or an SQL/JSON path starting with '$' (e.g. "$.a.b").
+// The column may be qualified with its table (e.g. "users.data") and is quoted as an identifier.
+func JSONBPath(column, path string) JSONBExpr {
+	e := JSONBExpr{column: quoteIdentifier(column)}
+	switch {
+	case path == "":
+	case strings.HasPrefix(path, "$"):
+		e.jsonPath = path
+	case strings.HasPrefix(path, "/"):
+		for _, ref := range strings.Split(path[1:], "/") {
+			e.pointer = append(e.pointer, strings.NewReplacer("~1", "/", "~0", "~").Replace(ref))
+		}
+	default:
+		e.err = fmt.Errorf("invalid JSONB path %q: expected a JSON pointer or an SQL/JSON path", path)
+	}
+	return e
+}
+
+// quoteIdentifier quotes each dot separated part of an SQL identifier.
+func quoteIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
+
+// Expr returns the JSONB value expression itself, e.g. for SELECT or ORDER BY clauses.
+func (e JSONBExpr) Expr() (SQLFragment, error) {
+	if e.err != nil {
+		return SQLFragment{}, e.err
+	}
+	var args []any
+	switch {
+	case e.jsonPath != "":
+		return SQLFragment{SQL: "jsonb_path_query_first(" + e.column + ", $1::jsonpath)", Args: []any{e.jsonPath}}, nil
+	case len(e.pointer) == 0:
+		return SQLFragment{SQL: e.column}, nil
+	}
+	placeholders := make([]string, len(e.pointer))
+	for i, ref := range e.pointer {
+		placeholders[i] = "$" + strconv.Itoa(i+1) + "::text"
+		args = append(args, ref)
+	}
+	return SQLFragment{SQL: e.column + " #> ARRAY[" + strings.Join(placeholders, ", ") + "]", Args: args}, nil
+}
+
+// Text returns the value at the path as text, like the ->> and #>> operators.
+func (e JSONBExpr) Text() (SQLFragment, error) {
+	expr, err := e.Expr()
+	if err != nil {
+		return SQLFragment{}, err
+	}
+	expr.SQL = "(" + expr.SQL + ") #>> '{}'"
+	return expr, nil
+}
+
+// Eq returns a fragment matching rows whose value at the path equals v.
+func (e JSONBExpr) Eq(v any) (SQLFragment, error) { return e.compare("=", Dyn{Val: v}) }
+
+// Ne returns a fragment matching rows whose value at the path is present and does not equal v.
+func (e JSONBExpr) Ne(v any) (SQLFragment, error) { return e.compare("<>", Dyn{Val: v}) }
+
+// Lt returns a fragment matching rows whose value at the path is less than v in JSONB ordering.
+func (e JSONBExpr) Lt(v any) (SQLFragment, error) { return e.compare("<", Dyn{Val: v}) }
+
+// Lte returns a fragment matching rows whose value at the path is less than or equal to v in JSONB ordering.
+func (e JSONBExpr) Lte(v any) (SQLFragment, error) { return e.compare("<=", Dyn{Val: v}) }
+
+// Gt returns a fragment matching rows whose value at the path is greater than v in JSONB ordering.
+func (e JSONBExpr) Gt(v any) (SQLFragment, error) { return e.compare(">", Dyn{Val: v}) }
+
+// Gte returns a fragment matching rows whose value at the path is greater than or equal to v in JSONB ordering.
+func (e JSONBExpr) Gte(v any) (SQLFragment, error) { return e.compare(">=", Dyn{Val: v}) }
+
+// Contains returns a fragment matching rows whose value at the path contains d (the @> operator).
+func (e JSONBExpr) Contains(d Dyn) (SQLFragment, error) { return e.compare("@>", d) }
+
+// ContainedBy returns a fragment matching rows whose value at the path is contained by d (the <@ operator).
+func (e JSONBExpr) ContainedBy(d Dyn) (SQLFragment, error) { return e.compare("<@", d) }
+
+// Exists returns a fragment matching rows that have a value (including JSON null) at the path.
+func (e JSONBExpr) Exists() (SQLFragment, error) {
+	if e.jsonPath != "" && e.err == nil {
+		return SQLFragment{SQL: "jsonb_path_exists(" + e.column + ", $1::jsonpath)", Args: []any{e.jsonPath}}, nil
+	}
+	expr, err := e.Expr()
+	if err != nil {
+		return SQLFragment{}, err
+	}
+	expr.SQL += " IS NOT NULL"
+	return expr, nil
+}
+
+// compare encodes d like Dyn.Value but pinned to DialectPostgresJSONB rather than the default []byte,
+// since drivers such as lib/pq send []byte parameters as bytea, which cannot be cast to jsonb.
+// It keeps nil as JSON null, as comparing with SQL NULL would never match.
+func (e JSONBExpr) compare(op string, d Dyn) (SQLFragment, error) {
+	expr, err := e.Expr()
+	if err != nil {
+		return SQLFragment{}, err
+	}
+	arg, err := DynAs(d, DynSQLConfig{Dialect: DialectPostgresJSONB}).Value()
+	if err != nil {
+		return SQLFragment{}, err
+	}
+	expr.SQL += " " + op + " $" + strconv.Itoa(len(expr.Args)+1) + "::jsonb"
+	expr.Args = append(expr.Args, arg)
+	return expr, nil
+}
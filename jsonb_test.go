@@ -0,0 +1,113 @@
+package typx_test
+
+import (
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_JSONBPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		fragment func() (typx.SQLFragment, error)
+		want     typx.SQLFragment
+		wantErr  bool
+	}{
+		{
+			name:     "eq pointer",
+			fragment: func() (typx.SQLFragment, error) { return typx.JSONBPath("data", "/a/b").Eq("x") },
+			want:     typx.SQLFragment{SQL: `"data" #> ARRAY[$1::text, $2::text] = $3::jsonb`, Args: []any{"a", "b", `"x"`}},
+		},
+		{
+			name:     "escaped pointer and qualified column",
+			fragment: func() (typx.SQLFragment, error) { return typx.JSONBPath(`users.da"ta`, "/a~1b/0").Gt(1) },
+			want:     typx.SQLFragment{SQL: `"users"."da""ta" #> ARRAY[$1::text, $2::text] > $3::jsonb`, Args: []any{"a/b", "0", "1"}},
+		},
+		{
+			name: "contains whole column",
+			fragment: func() (typx.SQLFragment, error) {
+				return typx.JSONBPath("data", "").Contains(typx.Dyn{Val: map[string]any{"tags": []any{"a"}}})
+			},
+			want: typx.SQLFragment{SQL: `"data" @> $1::jsonb`, Args: []any{`{"tags":["a"]}`}},
+		},
+		{
+			name: "contained by",
+			fragment: func() (typx.SQLFragment, error) {
+				return typx.JSONBPath("data", "/tags").ContainedBy(typx.Dyn{Val: []any{"a", "b"}})
+			},
+			want: typx.SQLFragment{SQL: `"data" #> ARRAY[$1::text] <@ $2::jsonb`, Args: []any{"tags", `["a","b"]`}},
+		},
+		{
+			name:     "json path",
+			fragment: func() (typx.SQLFragment, error) { return typx.JSONBPath("data", "$.a[*].b").Ne(nil) },
+			want:     typx.SQLFragment{SQL: `jsonb_path_query_first("data", $1::jsonpath) <> $2::jsonb`, Args: []any{"$.a[*].b", "null"}},
+		},
+		{
+			name:     "exists pointer",
+			fragment: func() (typx.SQLFragment, error) { return typx.JSONBPath("data", "/a").Exists() },
+			want:     typx.SQLFragment{SQL: `"data" #> ARRAY[$1::text] IS NOT NULL`, Args: []any{"a"}},
+		},
+		{
+			name:     "exists json path",
+			fragment: func() (typx.SQLFragment, error) { return typx.JSONBPath("data", "$.a ? (@ > 1)").Exists() },
+			want:     typx.SQLFragment{SQL: `jsonb_path_exists("data", $1::jsonpath)`, Args: []any{"$.a ? (@ > 1)"}},
+		},
+		{
+			name:     "text",
+			fragment: func() (typx.SQLFragment, error) { return typx.JSONBPath("data", "/a").Text() },
+			want:     typx.SQLFragment{SQL: `("data" #> ARRAY[$1::text]) #>> '{}'`, Args: []any{"a"}},
+		},
+		{
+			name:     "injection attempt stays an argument",
+			fragment: func() (typx.SQLFragment, error) { return typx.JSONBPath("data", "/a'; DROP TABLE users; --").Lte("'") },
+			want:     typx.SQLFragment{SQL: `"data" #> ARRAY[$1::text] <= $2::jsonb`, Args: []any{"a'; DROP TABLE users; --", `"'"`}},
+		},
+		{
+			name:     "invalid path",
+			fragment: func() (typx.SQLFragment, error) { return typx.JSONBPath("data", "a.b").Eq(1) },
+			wantErr:  true,
+		},
+		{
+			name:     "invalid value",
+			fragment: func() (typx.SQLFragment, error) { return typx.JSONBPath("data", "/a").Eq(func() {}) },
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fragment()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_SQLFragment_And(t *testing.T) {
+	a, err := typx.JSONBPath("data", "/a").Eq(1)
+	assert.NoError(t, err)
+	b, err := typx.JSONBPath(`"$1"`, "").Lt(2)
+	assert.NoError(t, err)
+
+	got := a.And(b)
+	assert.Equal(t, `("data" #> ARRAY[$1::text] = $2::jsonb) AND ("""$1""" < $3::jsonb)`, got.SQL)
+	assert.Equal(t, []any{"a", "1", "2"}, got.Args)
+	assert.Equal(t, `("data" #> ARRAY[$3::text] = $4::jsonb) AND ("""$1""" < $5::jsonb)`, got.At(2))
+}
+
+func Test_JSONBPath_ArgsMatchDynValue(t *testing.T) {
+	for _, d := range []typx.Dyn{{Val: nil}, {Val: "x"}, {Val: map[string]any{"a": []any{1.5, true}}}} {
+		fragment, err := typx.JSONBPath("data", "").Contains(d)
+		assert.NoError(t, err)
+		value, err := d.Value()
+		assert.NoError(t, err)
+		assert.Equal(t, []any{string(value.([]byte))}, fragment.Args)
+	}
+
+	_, err := typx.JSONBPath("data", "").Eq("\x00")
+	assert.Error(t, err)
+}
@@ -0,0 +1,796 @@
+package typx
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MsgpackMarshaler is the interface implemented by types that can marshal themselves into MessagePack.
+type MsgpackMarshaler interface {
+	MarshalMsgpack() ([]byte, error)
+}
+
+// MsgpackUnmarshaler is the interface implemented by types that can unmarshal a MessagePack
+// representation of themselves. The input is a single, complete MessagePack object.
+type MsgpackUnmarshaler interface {
+	UnmarshalMsgpack([]byte) error
+}
+
+// MarshalMsgpack returns the MessagePack encoding of v.
+// Structs are encoded as maps keyed by the `msgpack` tag, the `json` tag or the field name (in that order),
+// unset Opt fields and fields tagged with omitempty that hold a zero value are omitted,
+// and time.Time is encoded as the timestamp extension type (-1).
+func MarshalMsgpack(v any) ([]byte, error) {
+	return appendMsgpack(nil, reflect.ValueOf(v))
+}
+
+// UnmarshalMsgpack decodes the MessagePack encoded data and stores the result in the value pointed to by v.
+// Unknown map keys are ignored when decoding into structs, so absent Opt fields are left unset.
+func UnmarshalMsgpack(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("cannot unmarshal msgpack into %T: expected a non-nil pointer", v)
+	}
+	d := msgpackDecoder{data: data}
+	if err := d.decodeInto(rv.Elem(), 0); err != nil {
+		return err
+	}
+	return d.end()
+}
+
+const (
+	msgpackNil   = 0xc0
+	msgpackFalse = 0xc2
+	msgpackTrue  = 0xc3
+
+	msgpackMaxDepth      = 512
+	msgpackExtTimestamp  = -1
+	msgpackTimestamp32Up = 1 << 32
+	msgpackTimestamp64Up = 1 << 34
+)
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	byteType = reflect.TypeFor[byte]()
+)
+
+// optional is implemented by Opt[T] so that encoders can omit unset fields.
+type optional interface{ isSet() bool }
+
+func appendMsgpack(buf []byte, v reflect.Value) ([]byte, error) {
+	return appendMsgpackDepth(buf, v, 0)
+}
+
+func appendMsgpackDepth(buf []byte, v reflect.Value, depth int) ([]byte, error) {
+	if depth > msgpackMaxDepth {
+		return nil, errors.New("cannot marshal msgpack: maximum nesting depth exceeded")
+	}
+	if !v.IsValid() {
+		return append(buf, msgpackNil), nil
+	}
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return append(buf, msgpackNil), nil
+	}
+	if m, ok := v.Interface().(MsgpackMarshaler); ok {
+		data, err := m.MarshalMsgpack()
+		if err != nil {
+			return nil, err
+		}
+		return append(buf, data...), nil
+	}
+	if v.Type() == timeType {
+		return appendMsgpackTime(buf, v.Interface().(time.Time)), nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, msgpackTrue), nil
+		}
+		return append(buf, msgpackFalse), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendMsgpackInt(buf, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendMsgpackUint(buf, v.Uint()), nil
+	case reflect.Float32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xca), math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcb), math.Float64bits(v.Float())), nil
+	case reflect.String:
+		return append(appendMsgpackHead(buf, 0xa0, 0xd9, 0xda, 0xdb, 32, v.Len()), v.String()...), nil
+	case reflect.Interface, reflect.Pointer:
+		return appendMsgpackDepth(buf, v.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return append(buf, msgpackNil), nil
+		}
+		if v.Type().Elem() == byteType {
+			buf = appendMsgpackHead(buf, 0, 0xc4, 0xc5, 0xc6, 0, v.Len())
+			for i := range v.Len() {
+				buf = append(buf, byte(v.Index(i).Uint()))
+			}
+			return buf, nil
+		}
+		buf = appendMsgpackHead(buf, 0x90, 0, 0xdc, 0xdd, 16, v.Len())
+		for i := range v.Len() {
+			var err error
+			if buf, err = appendMsgpackDepth(buf, v.Index(i), depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return append(buf, msgpackNil), nil
+		}
+		keys := v.MapKeys()
+		if v.Type().Key().Kind() == reflect.String {
+			slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) })
+		}
+		buf = appendMsgpackHead(buf, 0x80, 0, 0xde, 0xdf, 16, len(keys))
+		for _, k := range keys {
+			var err error
+			if buf, err = appendMsgpackDepth(buf, k, depth+1); err != nil {
+				return nil, err
+			}
+			if buf, err = appendMsgpackDepth(buf, v.MapIndex(k), depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case reflect.Struct:
+		if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+			text, err := m.MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			return append(appendMsgpackHead(buf, 0xa0, 0xd9, 0xda, 0xdb, 32, len(text)), text...), nil
+		}
+		return appendMsgpackStruct(buf, v, depth)
+	}
+	return nil, fmt.Errorf("cannot marshal %s as msgpack", v.Type())
+}
+
+func appendMsgpackStruct(buf []byte, v reflect.Value, depth int) ([]byte, error) {
+	fields := msgpackFieldsOf(v.Type())
+	present := make([]reflect.Value, len(fields))
+	n := 0
+	for i, f := range fields {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			continue // the field is promoted from a nil embedded pointer
+		}
+		if opt, ok := fv.Interface().(optional); ok && !opt.isSet() {
+			continue
+		}
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		present[i] = fv
+		n++
+	}
+	buf = appendMsgpackHead(buf, 0x80, 0, 0xde, 0xdf, 16, n)
+	for i, f := range fields {
+		if !present[i].IsValid() {
+			continue
+		}
+		buf = append(appendMsgpackHead(buf, 0xa0, 0xd9, 0xda, 0xdb, 32, len(f.name)), f.name...)
+		var err error
+		if buf, err = appendMsgpackDepth(buf, present[i], depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// appendMsgpackHead appends a length prefixed header. A zero fix or 8-bit code means the format has no such variant.
+func appendMsgpackHead(buf []byte, fix, code8, code16, code32 byte, fixMax, n int) []byte {
+	switch {
+	case fix != 0 && n < fixMax:
+		return append(buf, fix|byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		return append(buf, code8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(buf, code32), uint32(n))
+	}
+}
+
+func appendMsgpackInt(buf []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendMsgpackUint(buf, uint64(i))
+	case i >= -32:
+		return append(buf, byte(i))
+	case i >= math.MinInt8:
+		return append(buf, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(i))
+	}
+}
+
+func appendMsgpackUint(buf []byte, u uint64) []byte {
+	switch {
+	case u <= 0x7f:
+		return append(buf, byte(u))
+	case u <= math.MaxUint8:
+		return append(buf, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xce), uint32(u))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcf), u)
+	}
+}
+
+// appendMsgpackTime appends t using the smallest of the timestamp 32, 64 and 96 formats.
+func appendMsgpackTime(buf []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case sec >= 0 && sec < msgpackTimestamp32Up && nsec == 0:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd6, 0xff), uint32(sec))
+	case sec >= 0 && sec < msgpackTimestamp64Up:
+		return binary.BigEndian.AppendUint64(append(buf, 0xd7, 0xff), nsec<<34|uint64(sec))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xc7, 12, 0xff), uint32(nsec))
+		return binary.BigEndian.AppendUint64(buf, uint64(sec))
+	}
+}
+
+type msgpackField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var msgpackFieldCache sync.Map // map[reflect.Type][]msgpackField
+
+// msgpackFieldsOf returns the encodable fields of a struct type, promoting the fields of
+// untagged embedded structs and struct pointers the same way encoding/json does.
+func msgpackFieldsOf(t reflect.Type) []msgpackField {
+	if cached, ok := msgpackFieldCache.Load(t); ok {
+		return cached.([]msgpackField)
+	}
+	var fields []msgpackField
+	for _, sf := range reflect.VisibleFields(t) {
+		if !sf.IsExported() {
+			continue
+		}
+		name, opts, tagged := fieldTag(sf, "msgpack")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if sf.Anonymous && !tagged && (sf.Type.Kind() == reflect.Struct ||
+			sf.Type.Kind() == reflect.Pointer && sf.Type.Elem().Kind() == reflect.Struct) {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, msgpackField{name: name, index: sf.Index, omitEmpty: hasTagOption(opts, "omitempty")})
+	}
+	msgpackFieldCache.Store(t, fields)
+	return fields
+}
+
+// fieldTag returns the name and options of the given tag, falling back to the json tag.
+func fieldTag(sf reflect.StructField, key string) (name, opts string, ok bool) {
+	tag, ok := sf.Tag.Lookup(key)
+	if !ok {
+		tag, ok = sf.Tag.Lookup("json")
+	}
+	name, opts, _ = strings.Cut(tag, ",")
+	return name, opts, ok && name != ""
+}
+
+func hasTagOption(opts, option string) bool {
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// viaEmbeddedPointer reports whether a promoted field is reached through an embedded pointer.
+func viaEmbeddedPointer(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		f := t.Field(i)
+		if f.Type.Kind() == reflect.Pointer {
+			return true
+		}
+		t = f.Type
+	}
+	return false
+}
+
+// fieldByIndexAlloc returns the nested field of v by index, allocating the nil embedded pointers on its way.
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %s", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+type msgpackDecoder struct {
+	data []byte
+	off  int
+}
+
+var errMsgpackTruncated = errors.New("cannot unmarshal msgpack: unexpected end of data")
+
+// unmarshalMsgpackAny decodes a single MessagePack object into a JSON-shaped tree.
+func unmarshalMsgpackAny(data []byte) (any, error) {
+	d := msgpackDecoder{data: data}
+	v, err := d.decodeAny(0)
+	if err != nil {
+		return nil, err
+	}
+	return v, d.end()
+}
+
+func (d *msgpackDecoder) end() error {
+	if d.off != len(d.data) {
+		return fmt.Errorf("cannot unmarshal msgpack: %d trailing bytes", len(d.data)-d.off)
+	}
+	return nil
+}
+
+func (d *msgpackDecoder) next(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data)-d.off {
+		return nil, errMsgpackTruncated
+	}
+	b := d.data[d.off : d.off+n]
+	d.off += n
+	return b, nil
+}
+
+func (d *msgpackDecoder) uint(size int) (uint64, error) {
+	b, err := d.next(size)
+	if err != nil {
+		return 0, err
+	}
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+func (d *msgpackDecoder) peekNil() bool {
+	if d.off < len(d.data) && d.data[d.off] == msgpackNil {
+		d.off++
+		return true
+	}
+	return false
+}
+
+// decodeAny decodes the next object into nil, bool, int64, uint64, float64, string, []byte,
+// []any, map[string]any or time.Time.
+func (d *msgpackDecoder) decodeAny(depth int) (any, error) {
+	if depth > msgpackMaxDepth {
+		return nil, errors.New("cannot unmarshal msgpack: maximum nesting depth exceeded")
+	}
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c >= 0xa0 && c <= 0xbf:
+		s, err := d.next(int(c & 0x1f))
+		return string(s), err
+	case c >= 0x90 && c <= 0x9f:
+		return d.decodeArray(int(c&0x0f), depth)
+	case c >= 0x80 && c <= 0x8f:
+		return d.decodeMap(int(c&0x0f), depth)
+	}
+	switch c {
+	case msgpackNil:
+		return nil, nil
+	case msgpackFalse:
+		return false, nil
+	case msgpackTrue:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if u > math.MaxInt64 {
+			return u, nil
+		}
+		return int64(u), nil
+	case 0xd0:
+		u, err := d.uint(1)
+		return int64(int8(u)), err
+	case 0xd1:
+		u, err := d.uint(2)
+		return int64(int16(u)), err
+	case 0xd2:
+		u, err := d.uint(4)
+		return int64(int32(u)), err
+	case 0xd3:
+		u, err := d.uint(8)
+		return int64(u), err
+	case 0xca:
+		u, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := d.uint(8)
+		return math.Float64frombits(u), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.next(int(n))
+		return string(s), err
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.next(int(n))
+		return slices.Clone(s), err
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(int(n), depth)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(int(n), depth)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xc7, 0xc8, 0xc9:
+		d.off--
+		return d.decodeExt()
+	}
+	return nil, fmt.Errorf("cannot unmarshal msgpack: invalid type code 0x%02x", c)
+}
+
+func (d *msgpackDecoder) decodeArray(n, depth int) ([]any, error) {
+	if n > len(d.data)-d.off {
+		return nil, errMsgpackTruncated
+	}
+	arr := make([]any, n)
+	for i := range arr {
+		var err error
+		if arr[i], err = d.decodeAny(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
+}
+
+func (d *msgpackDecoder) decodeMap(n, depth int) (map[string]any, error) {
+	if n > (len(d.data)-d.off)/2 {
+		return nil, errMsgpackTruncated
+	}
+	m := make(map[string]any, n)
+	for range n {
+		k, err := d.decodeAny(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot unmarshal msgpack: unsupported map key of type %T, expected string", k)
+		}
+		if m[key], err = d.decodeAny(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// decodeExt decodes an extension object. Only the timestamp extension type is supported.
+func (d *msgpackDecoder) decodeExt() (time.Time, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var n uint64
+	switch c := b[0]; c {
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		n = 1 << (c - 0xd4)
+	case 0xc7, 0xc8, 0xc9:
+		if n, err = d.uint(1 << (c - 0xc7)); err != nil {
+			return time.Time{}, err
+		}
+	default:
+		return time.Time{}, fmt.Errorf("cannot unmarshal msgpack: invalid extension type code 0x%02x", c)
+	}
+	typ, err := d.uint(1)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if int8(typ) != msgpackExtTimestamp {
+		return time.Time{}, fmt.Errorf("cannot unmarshal msgpack: unsupported extension type %d", int8(typ))
+	}
+	payload, err := d.next(int(n))
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch len(payload) {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(payload)), 0).UTC(), nil
+	case 8:
+		v := binary.BigEndian.Uint64(payload)
+		return time.Unix(int64(v&(msgpackTimestamp64Up-1)), int64(v>>34)).UTC(), nil
+	case 12:
+		nsec := binary.BigEndian.Uint32(payload[:4])
+		return time.Unix(int64(binary.BigEndian.Uint64(payload[4:])), int64(nsec)).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("cannot unmarshal msgpack: invalid timestamp length %d", len(payload))
+}
+
+// skip advances past the next object without decoding it.
+func (d *msgpackDecoder) skip(depth int) error {
+	_, err := d.decodeAny(depth)
+	return err
+}
+
+// containerLen reads an array or map header and returns its length.
+func (d *msgpackDecoder) containerLen(fix byte, fixMask byte, code16 byte) (int, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, err
+	}
+	c := b[0]
+	switch {
+	case c&^fixMask == fix:
+		return int(c & fixMask), nil
+	case c == code16, c == code16+1:
+		n, err := d.uint(2 << (c - code16))
+		if err != nil {
+			return 0, err
+		}
+		if n > uint64(len(d.data)-d.off) {
+			return 0, errMsgpackTruncated
+		}
+		return int(n), nil
+	}
+	d.off--
+	return 0, fmt.Errorf("cannot unmarshal msgpack: unexpected type code 0x%02x", c)
+}
+
+// decodeInto decodes the next object into v, honoring MsgpackUnmarshaler implementations.
+func (d *msgpackDecoder) decodeInto(v reflect.Value, depth int) error {
+	if depth > msgpackMaxDepth {
+		return errors.New("cannot unmarshal msgpack: maximum nesting depth exceeded")
+	}
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(MsgpackUnmarshaler); ok {
+			start := d.off
+			if err := d.skip(depth); err != nil {
+				return err
+			}
+			return u.UnmarshalMsgpack(d.data[start:d.off])
+		}
+	}
+	if d.peekNil() {
+		v.SetZero()
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decodeInto(v.Elem(), depth+1)
+	case reflect.Slice:
+		if v.Type().Elem() == byteType {
+			break
+		}
+		n, err := d.containerLen(0x90, 0x0f, 0xdc)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := range n {
+			if err := d.decodeInto(v.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Array:
+		if v.Type().Elem() == byteType {
+			break
+		}
+		n, err := d.containerLen(0x90, 0x0f, 0xdc)
+		if err != nil {
+			return err
+		}
+		v.SetZero()
+		for i := range n {
+			if i >= v.Len() {
+				if err := d.skip(depth + 1); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.decodeInto(v.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		n, err := d.containerLen(0x80, 0x0f, 0xde)
+		if err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), n))
+		}
+		for range n {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := d.decodeInto(key, depth+1); err != nil {
+				return err
+			}
+			val := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decodeInto(val, depth+1); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, val)
+		}
+		return nil
+	case reflect.Struct:
+		if v.Type() == timeType || reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+			break
+		}
+		n, err := d.containerLen(0x80, 0x0f, 0xde)
+		if err != nil {
+			return err
+		}
+		fields := msgpackFieldsOf(v.Type())
+		for range n {
+			k, err := d.decodeAny(depth + 1)
+			if err != nil {
+				return err
+			}
+			key, _ := k.(string)
+			idx := slices.IndexFunc(fields, func(f msgpackField) bool { return f.name == key })
+			if idx < 0 {
+				if err := d.skip(depth + 1); err != nil {
+					return err
+				}
+				continue
+			}
+			fv, err := fieldByIndexAlloc(v, fields[idx].index)
+			if err != nil {
+				return fmt.Errorf("cannot unmarshal msgpack into %s: %w", v.Type(), err)
+			}
+			if err := d.decodeInto(fv, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	val, err := d.decodeAny(depth)
+	if err != nil {
+		return err
+	}
+	return assignMsgpack(v, val)
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// assignMsgpack stores a decoded scalar into v, converting between compatible kinds.
+func assignMsgpack(v reflect.Value, val any) error {
+	rv := reflect.ValueOf(val)
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if s, ok := val.(string); ok {
+				return u.UnmarshalText([]byte(s))
+			}
+		}
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(rv)
+			return nil
+		}
+	case reflect.Bool:
+		if b, ok := val.(bool); ok {
+			v.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch n := val.(type) {
+		case int64:
+			if !v.OverflowInt(n) {
+				v.SetInt(n)
+				return nil
+			}
+		case uint64:
+			return fmt.Errorf("cannot unmarshal msgpack: integer overflows %s", v.Type())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch n := val.(type) {
+		case int64:
+			if n >= 0 && !v.OverflowUint(uint64(n)) {
+				v.SetUint(uint64(n))
+				return nil
+			}
+		case uint64:
+			if !v.OverflowUint(n) {
+				v.SetUint(n)
+				return nil
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		switch n := val.(type) {
+		case float64:
+			v.SetFloat(n)
+			return nil
+		case int64:
+			v.SetFloat(float64(n))
+			return nil
+		case uint64:
+			v.SetFloat(float64(n))
+			return nil
+		}
+	case reflect.String:
+		switch s := val.(type) {
+		case string:
+			v.SetString(s)
+			return nil
+		case []byte:
+			v.SetString(string(s))
+			return nil
+		}
+	case reflect.Slice:
+		switch s := val.(type) {
+		case []byte:
+			v.SetBytes(s)
+			return nil
+		case string:
+			v.SetBytes([]byte(s))
+			return nil
+		}
+	case reflect.Array:
+		if s, ok := val.([]byte); ok && len(s) == v.Len() {
+			reflect.Copy(v, reflect.ValueOf(s))
+			return nil
+		}
+	case reflect.Struct:
+		if t, ok := val.(time.Time); ok && v.Type() == timeType {
+			v.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot unmarshal msgpack %T into %s", val, v.Type())
+}
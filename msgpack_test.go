@@ -0,0 +1,159 @@
+package typx_test
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Nil_Msgpack_Marshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value typx.MsgpackMarshaler
+		want  string
+	}{
+		{name: "nil", value: typx.Nil[int]{}, want: "c0"},
+		{name: "int", value: typx.NilFrom(-33), want: "d0df"},
+		{name: "string", value: typx.NilFrom("abc"), want: "a3616263"},
+		{name: "bytes", value: typx.NilFrom([]byte{1, 2}), want: "c4020102"},
+		{name: "zero value", value: typx.NilFrom(0), want: "00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.value.MarshalMsgpack()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, hex.EncodeToString(got))
+		})
+	}
+}
+
+func Test_Nil_Msgpack_Unmarshal(t *testing.T) {
+	randomID := uuid.New()
+	data, err := typx.MarshalMsgpack(typx.NilFrom(randomID))
+	assert.NoError(t, err)
+	var id typx.Nil[uuid.UUID]
+	assert.NoError(t, id.UnmarshalMsgpack(data))
+	assert.Equal(t, typx.NilFrom(randomID), id)
+
+	s := typx.NilFrom("previous")
+	assert.NoError(t, s.UnmarshalMsgpack([]byte{0xc0}))
+	assert.Equal(t, typx.Nil[string]{}, s)
+
+	var i typx.Nil[int8]
+	assert.Error(t, i.UnmarshalMsgpack([]byte{0xcd, 0x01, 0x00}))
+	assert.False(t, i.NotNil)
+}
+
+func Test_Opt_Msgpack(t *testing.T) {
+	got, err := typx.Opt[int]{}.MarshalMsgpack()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xc0}, got)
+
+	var o typx.Opt[typx.Nil[int]]
+	assert.NoError(t, o.UnmarshalMsgpack([]byte{0xc0}))
+	assert.Equal(t, typx.OptFrom(typx.Nil[int]{}), o)
+}
+
+func Test_Dyn_Msgpack(t *testing.T) {
+	ts := time.Date(2025, 6, 28, 12, 0, 0, 500, time.UTC)
+	tests := []struct {
+		name  string
+		value typx.Dyn
+		want  typx.Dyn
+	}{
+		{name: "nil", value: typx.Dyn{Val: nil}, want: typx.Dyn{Val: nil}},
+		{name: "int", value: typx.Dyn{Val: 300}, want: typx.Dyn{Val: int64(300)}},
+		{name: "float", value: typx.Dyn{Val: 1.5}, want: typx.Dyn{Val: 1.5}},
+		{name: "time", value: typx.Dyn{Val: ts}, want: typx.Dyn{Val: ts}},
+		{name: "time32", value: typx.Dyn{Val: ts.Truncate(time.Second)}, want: typx.Dyn{Val: ts.Truncate(time.Second)}},
+		{name: "time96", value: typx.Dyn{Val: time.Date(1900, 1, 1, 0, 0, 0, 1, time.UTC)}, want: typx.Dyn{Val: time.Date(1900, 1, 1, 0, 0, 0, 1, time.UTC)}},
+		{
+			name:  "object",
+			value: typx.Dyn{Val: map[string]any{"a": []any{"x", true}, "b": typx.Dyn{Val: []byte("raw")}}},
+			want:  typx.Dyn{Val: map[string]any{"a": []any{"x", true}, "b": []byte("raw")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.value.MarshalMsgpack()
+			assert.NoError(t, err)
+			got := typx.Dyn{}
+			assert.NoError(t, got.UnmarshalMsgpack(data))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Error(t, (&typx.Dyn{}).UnmarshalMsgpack([]byte{0x81, 0x01, 0x02}), "non-string map key")
+	assert.Error(t, (&typx.Dyn{}).UnmarshalMsgpack([]byte{0x92, 0x01}), "truncated")
+	assert.Error(t, (&typx.Dyn{}).UnmarshalMsgpack([]byte{0xd4, 0x01, 0x00}), "unknown extension")
+}
+
+type msgpackUser struct {
+	ID       uuid.UUID                  `msgpack:"id"`
+	Name     string                     `json:"name"`
+	Landline typx.Nil[string]           `msgpack:"landline"`
+	Mobile   typx.Opt[string]           `msgpack:"mobile"`
+	Fax      typx.Opt[typx.Nil[string]] `msgpack:"fax"`
+	Info     typx.Dyn                   `msgpack:"info"`
+	Tags     []string                   `msgpack:"tags,omitempty"`
+	Internal string                     `msgpack:"-"`
+}
+
+func Test_Msgpack_Struct(t *testing.T) {
+	user := msgpackUser{
+		ID:       uuid.New(),
+		Name:     "name",
+		Fax:      typx.OptFrom(typx.Nil[string]{}),
+		Info:     typx.Dyn{Val: map[string]any{"age": int64(30)}},
+		Internal: "secret",
+	}
+	data, err := typx.MarshalMsgpack(user)
+	assert.NoError(t, err)
+
+	var tree typx.Dyn
+	assert.NoError(t, tree.UnmarshalMsgpack(data))
+	assert.Equal(t, map[string]any{
+		"id":       user.ID[:],
+		"name":     "name",
+		"landline": nil,
+		"fax":      nil,
+		"info":     map[string]any{"age": int64(30)},
+	}, tree.Val)
+
+	var got msgpackUser
+	assert.NoError(t, typx.UnmarshalMsgpack(data, &got))
+	user.Internal = ""
+	assert.Equal(t, user, got)
+
+	assert.Error(t, typx.UnmarshalMsgpack(data, got))
+}
+
+type MsgpackAudit struct {
+	CreatedBy string `msgpack:"created_by"`
+}
+
+type msgpackPost struct {
+	*MsgpackAudit
+	Title string `msgpack:"title"`
+}
+
+func Test_Msgpack_EmbeddedPointer(t *testing.T) {
+	data, err := typx.MarshalMsgpack(msgpackPost{MsgpackAudit: &MsgpackAudit{CreatedBy: "admin"}, Title: "title"})
+	assert.NoError(t, err)
+	var tree typx.Dyn
+	assert.NoError(t, tree.UnmarshalMsgpack(data))
+	assert.Equal(t, map[string]any{"created_by": "admin", "title": "title"}, tree.Val)
+
+	var got msgpackPost
+	assert.NoError(t, typx.UnmarshalMsgpack(data, &got))
+	assert.Equal(t, msgpackPost{MsgpackAudit: &MsgpackAudit{CreatedBy: "admin"}, Title: "title"}, got)
+
+	data, err = typx.MarshalMsgpack(msgpackPost{Title: "title"})
+	assert.NoError(t, err)
+	assert.NoError(t, tree.UnmarshalMsgpack(data))
+	assert.Equal(t, map[string]any{"title": "title"}, tree.Val)
+}
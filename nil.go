@@ -5,6 +5,7 @@ import (
 	"database/sql/driver"
 	"encoding"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -39,6 +40,12 @@ func (n Nil[T]) Ptr() *T {
 	return &n.Val
 }
 
+// nilTarget marks the Nil as not nil and returns a pointer to decode its value into.
+func (n *Nil[T]) nilTarget() any {
+	n.NotNil = true
+	return &n.Val
+}
+
 // Scan implements the sql.Scanner interface.
 func (n *Nil[T]) Scan(src any) error {
 	n.NotNil = false
@@ -72,7 +79,7 @@ func (n *Nil[T]) Scan(src any) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("cannot scan %v into Nil[%T]", src, n.Val)
+	return fmt.Errorf("cannot scan %T into Nil[%T]", src, n.Val)
 }
 
 // Value implements the driver.Valuer interface.
@@ -205,7 +212,7 @@ func (n *Nil[T]) UnmarshalJSON(data []byte) error {
 // MarshalBSONValue implements the bson.ValueMarshaler interface.
 func (n Nil[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	if !n.NotNil {
-		return bson.MarshalValue(new(T))
+		return bson.MarshalValue((*T)(nil))
 	}
 	return bson.MarshalValue(n.Val)
 }
@@ -223,3 +230,97 @@ func (n *Nil[T]) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
 	n.NotNil = true
 	return nil
 }
+
+// MarshalMsgpack implements the MsgpackMarshaler interface.
+func (n Nil[T]) MarshalMsgpack() ([]byte, error) {
+	if !n.NotNil {
+		return []byte{msgpackNil}, nil
+	}
+	return MarshalMsgpack(n.Val)
+}
+
+// UnmarshalMsgpack implements the MsgpackUnmarshaler interface.
+func (n *Nil[T]) UnmarshalMsgpack(data []byte) error {
+	n.NotNil = false
+	if len(data) == 1 && data[0] == msgpackNil {
+		n.Val = *new(T)
+		return nil
+	}
+	if err := UnmarshalMsgpack(data, &n.Val); err != nil {
+		return err
+	}
+	n.NotNil = true
+	return nil
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+// A nil value is encoded as an empty element with the xsi:nil="true" attribute.
+func (n Nil[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !n.NotNil {
+		return writeXSINil(e, start)
+	}
+	return e.EncodeElement(n.Val, start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// An element with the xsi:nil="true" attribute is decoded as nil.
+func (n *Nil[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	n.NotNil = false
+	if isXSINil(start) {
+		n.Val = *new(T)
+		return d.Skip()
+	}
+	if err := d.DecodeElement(&n.Val, &start); err != nil {
+		return err
+	}
+	n.NotNil = true
+	return nil
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A nil value omits the attribute.
+func (n Nil[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if !n.NotNil {
+		return xml.Attr{}, nil
+	}
+	if marshaler, ok := any(n.Val).(xml.MarshalerAttr); ok {
+		return marshaler.MarshalXMLAttr(name)
+	}
+	value, err := marshalXMLAttrValue(n.Val)
+	if err != nil {
+		return xml.Attr{}, err
+	}
+	return xml.Attr{Name: name, Value: value}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+// It is only called for present attributes, so an absent attribute leaves the value nil.
+func (n *Nil[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	n.NotNil = false
+	if unmarshaler, ok := any(&n.Val).(xml.UnmarshalerAttr); ok {
+		if err := unmarshaler.UnmarshalXMLAttr(attr); err != nil {
+			return err
+		}
+	} else if err := unmarshalXMLAttrValue(&n.Val, attr.Value); err != nil {
+		return err
+	}
+	n.NotNil = true
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (n Nil[T]) GobEncode() ([]byte, error) {
+	return gobEncodeOptional(n.NotNil, n.Val)
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (n *Nil[T]) GobDecode(data []byte) error {
+	n.NotNil = false
+	n.Val = *new(T)
+	notNil, err := gobDecodeOptional(data, &n.Val)
+	if err != nil {
+		return err
+	}
+	n.NotNil = notNil
+	return nil
+}
@@ -384,6 +384,17 @@ func Test_Nil_JSON_UnMarshal(t *testing.T) {
 	}
 }
 
+func Test_Nil_JSON_UnMarshal_Replaces(t *testing.T) {
+	m := typx.NilFrom(map[string]int{"a": 1})
+	assert.NoError(t, json.Unmarshal([]byte(`{"b":2}`), &m))
+	assert.Equal(t, typx.NilFrom(map[string]int{"b": 2}), m)
+
+	type pair struct{ A, B int }
+	p := typx.NilFrom(pair{A: 1})
+	assert.NoError(t, json.Unmarshal([]byte(`{"B":2}`), &p))
+	assert.Equal(t, typx.NilFrom(pair{B: 2}), p)
+}
+
 func Test_Nil_BSON_Marshal(t *testing.T) {
 	randomID := uuid.New()
 	randomBSON, _ := bson.Marshal(struct{ ID uuid.UUID }{ID: randomID})
@@ -465,3 +476,44 @@ func Test_Nil_BSON_UnMarshal(t *testing.T) {
 		})
 	}
 }
+
+func Test_Nil_BSON_Null(t *testing.T) {
+	typ, data, err := typx.Nil[string]{}.MarshalBSONValue()
+	assert.NoError(t, err)
+	assert.Equal(t, bson.TypeNull, typ)
+	assert.Empty(t, data)
+
+	type doc struct{ Name typx.Nil[string] }
+	raw, err := bson.Marshal(doc{})
+	assert.NoError(t, err)
+	var m bson.M
+	assert.NoError(t, bson.Unmarshal(raw, &m))
+	assert.Equal(t, bson.M{"name": nil}, m)
+	var decoded doc
+	assert.NoError(t, bson.Unmarshal(raw, &decoded))
+	assert.Equal(t, doc{}, decoded)
+}
+
+func Test_Nil_BSON_LegacyZeroValue(t *testing.T) {
+	// Earlier versions encoded nil values as the zero value of T, which decodes into a non-nil Nil.
+	type doc struct {
+		Name  typx.Nil[string]
+		Count typx.Nil[int32]
+		Info  typx.Nil[struct{ A string }]
+	}
+	raw, err := bson.Marshal(bson.M{"name": "", "count": int32(0), "info": bson.M{}})
+	assert.NoError(t, err)
+	var decoded doc
+	assert.NoError(t, bson.Unmarshal(raw, &decoded))
+	assert.Equal(t, doc{
+		Name:  typx.NilFrom(""),
+		Count: typx.NilFrom[int32](0),
+		Info:  typx.NilFrom(struct{ A string }{}),
+	}, decoded)
+
+	raw, err = bson.Marshal(bson.M{"name": nil, "count": nil, "info": nil})
+	assert.NoError(t, err)
+	decoded = doc{}
+	assert.NoError(t, bson.Unmarshal(raw, &decoded))
+	assert.Equal(t, doc{}, decoded)
+}
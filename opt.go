@@ -1,5 +1,7 @@
 package typx
 
+import "encoding/xml"
+
 // Opt is a type that can be used to represent an optional value.
 // It should be used for fields that are optional and might not be present.
 // For nullable values, use Nil[T] instead.
@@ -20,3 +22,103 @@ func OptFromPtr[T any](ptr *T) Opt[T] {
 	}
 	return Opt[T]{Val: *ptr, Set: true}
 }
+
+func (o Opt[T]) isSet() bool { return o.Set }
+
+// setTarget marks the Opt as set and returns a pointer to decode or scan its value into.
+func (o *Opt[T]) setTarget() any {
+	o.Set = true
+	return &o.Val
+}
+
+// IsZero reports whether the value is unset, even if Val holds a stale value.
+// It makes `omitzero` (encoding/json), `omitempty` (BSON, through bsoncodec.Zeroer) and `omitempty`
+// (gopkg.in/yaml.v3) omit unset values, which were only omitted when both Val and Set were zero before.
+func (o Opt[T]) IsZero() bool { return !o.Set }
+
+// MarshalMsgpack implements the MsgpackMarshaler interface.
+// An unset Opt is encoded as nil on its own, and omitted entirely when it is a struct field.
+func (o Opt[T]) MarshalMsgpack() ([]byte, error) {
+	if !o.Set {
+		return []byte{msgpackNil}, nil
+	}
+	return MarshalMsgpack(o.Val)
+}
+
+// UnmarshalMsgpack implements the MsgpackUnmarshaler interface.
+// Any present value (including nil) marks the Opt as set.
+func (o *Opt[T]) UnmarshalMsgpack(data []byte) error {
+	o.Set = false
+	if err := UnmarshalMsgpack(data, &o.Val); err != nil {
+		return err
+	}
+	o.Set = true
+	return nil
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+// An unset value is omitted.
+func (o Opt[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !o.Set {
+		return nil
+	}
+	return e.EncodeElement(o.Val, start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Any present element marks the Opt as set, while absent elements leave it unset.
+func (o *Opt[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	o.Set = false
+	if err := d.DecodeElement(&o.Val, &start); err != nil {
+		return err
+	}
+	o.Set = true
+	return nil
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// An unset value omits the attribute.
+func (o Opt[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if !o.Set {
+		return xml.Attr{}, nil
+	}
+	if marshaler, ok := any(o.Val).(xml.MarshalerAttr); ok {
+		return marshaler.MarshalXMLAttr(name)
+	}
+	value, err := marshalXMLAttrValue(o.Val)
+	if err != nil {
+		return xml.Attr{}, err
+	}
+	return xml.Attr{Name: name, Value: value}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (o *Opt[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	o.Set = false
+	if unmarshaler, ok := any(&o.Val).(xml.UnmarshalerAttr); ok {
+		if err := unmarshaler.UnmarshalXMLAttr(attr); err != nil {
+			return err
+		}
+	} else if err := unmarshalXMLAttrValue(&o.Val, attr.Value); err != nil {
+		return err
+	}
+	o.Set = true
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (o Opt[T]) GobEncode() ([]byte, error) {
+	return gobEncodeOptional(o.Set, o.Val)
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (o *Opt[T]) GobDecode(data []byte) error {
+	o.Set = false
+	o.Val = *new(T)
+	set, err := gobDecodeOptional(data, &o.Val)
+	if err != nil {
+		return err
+	}
+	o.Set = set
+	return nil
+}
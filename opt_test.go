@@ -0,0 +1,48 @@
+package typx_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Opt_IsZero(t *testing.T) {
+	assert.True(t, typx.Opt[string]{}.IsZero())
+	assert.True(t, typx.Opt[string]{Val: "stale"}.IsZero())
+	assert.False(t, typx.OptFrom("").IsZero())
+
+	type doc struct {
+		Name typx.Opt[string] `json:"name,omitzero" bson:"name,omitempty" yaml:"name,omitempty"`
+	}
+	unset := doc{Name: typx.Opt[string]{Val: "stale"}}
+	set := doc{Name: typx.OptFrom("")}
+
+	data, err := json.Marshal(unset)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(data))
+	data, err = json.Marshal(set)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":{"val":"","set":true}}`, string(data))
+
+	var m bson.M
+	data, err = bson.Marshal(unset)
+	assert.NoError(t, err)
+	assert.NoError(t, bson.Unmarshal(data, &m))
+	assert.Equal(t, bson.M{}, m)
+	data, err = bson.Marshal(set)
+	assert.NoError(t, err)
+	m = nil
+	assert.NoError(t, bson.Unmarshal(data, &m))
+	assert.Equal(t, bson.M{"name": bson.M{"val": "", "set": true}}, m)
+
+	data, err = yaml.Marshal(unset)
+	assert.NoError(t, err)
+	assert.Equal(t, "{}\n", string(data))
+	data, err = yaml.Marshal(set)
+	assert.NoError(t, err)
+	assert.Equal(t, "name: \"\"\n", string(data))
+}
@@ -0,0 +1,398 @@
+package typx
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Range is a PostgreSQL range of T, e.g. Range[int] for int4range, Range[time.Time] for tstzrange or daterange.
+// A bound that is nil is infinite, and its inclusivity is ignored. A range is empty if Empty is true
+// or if its bounds do not include any value, e.g. [5,5) or [7,3].
+// T must be an integer, float or string kind, or have a Compare(T) int method like time.Time;
+// the comparing methods panic otherwise.
+// Discrete ranges are not canonicalized, so [1,4] and [1,5) are different values.
+// It implements interfaces for SQL (using the range text format), JSON and BSON encoding.
+type Range[T any] struct {
+	Lower    Nil[T]
+	Upper    Nil[T]
+	LowerInc bool
+	UpperInc bool
+	Empty    bool
+}
+
+// RangeFrom returns the range [lower,upper), which is how PostgreSQL canonicalizes discrete ranges.
+func RangeFrom[T any](lower, upper T) Range[T] {
+	return Range[T]{Lower: NilFrom(lower), Upper: NilFrom(upper), LowerInc: true}
+}
+
+// EmptyRange returns an empty range.
+func EmptyRange[T any]() Range[T] {
+	return Range[T]{Empty: true}
+}
+
+// IsEmpty reports whether the range does not include any value.
+func (r Range[T]) IsEmpty() bool {
+	return r.Empty || !boundsMeet(r.lower(), r.upper())
+}
+
+// Contains reports whether v is within the range.
+func (r Range[T]) Contains(v T) bool {
+	if r.IsEmpty() {
+		return false
+	}
+	point := rangeBound[T]{val: v, finite: true, inc: true}
+	return boundsMeet(r.lower(), point) && boundsMeet(point, r.upper())
+}
+
+// Overlaps reports whether both ranges have values in common, like the && operator.
+func (r Range[T]) Overlaps(other Range[T]) bool {
+	if r.IsEmpty() || other.IsEmpty() {
+		return false
+	}
+	return boundsMeet(r.lower(), other.upper()) && boundsMeet(other.lower(), r.upper())
+}
+
+// Intersect returns the values both ranges have in common, like the * operator.
+func (r Range[T]) Intersect(other Range[T]) Range[T] {
+	if !r.Overlaps(other) {
+		return EmptyRange[T]()
+	}
+	lower, upper := r.lower(), r.upper()
+	if compareLower(other.lower(), lower) > 0 {
+		lower = other.lower()
+	}
+	if compareUpper(other.upper(), upper) < 0 {
+		upper = other.upper()
+	}
+	return rangeOf(lower, upper)
+}
+
+// Union returns the range covering both ranges, like the + operator.
+// It returns an error if the ranges neither overlap nor are adjacent, as the result would not be contiguous.
+func (r Range[T]) Union(other Range[T]) (Range[T], error) {
+	switch {
+	case other.IsEmpty():
+		return r, nil
+	case r.IsEmpty():
+		return other, nil
+	case !r.Overlaps(other) && !r.adjacent(other) && !other.adjacent(r):
+		return Range[T]{}, errors.New("cannot union ranges: result would not be contiguous")
+	}
+	lower, upper := r.lower(), r.upper()
+	if compareLower(other.lower(), lower) < 0 {
+		lower = other.lower()
+	}
+	if compareUpper(other.upper(), upper) > 0 {
+		upper = other.upper()
+	}
+	return rangeOf(lower, upper), nil
+}
+
+// adjacent reports whether other starts exactly where r ends.
+func (r Range[T]) adjacent(other Range[T]) bool {
+	upper, lower := r.upper(), other.lower()
+	return upper.finite && lower.finite && upper.inc != lower.inc && compareValues(upper.val, lower.val) == 0
+}
+
+// Scan implements the sql.Scanner interface for the PostgreSQL range text format, e.g. [1,10), (,5] or empty.
+func (r *Range[T]) Scan(src any) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		return errors.New("cannot scan NULL into Range: use Nil[Range[T]] for nullable columns")
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return fmt.Errorf("cannot scan %T into Range: expected the range text format ([]byte or string)", src)
+	}
+	text = strings.TrimSpace(text)
+	if strings.EqualFold(text, "empty") {
+		*r = EmptyRange[T]()
+		return nil
+	}
+	if len(text) < 2 || (text[0] != '[' && text[0] != '(') || (text[len(text)-1] != ']' && text[len(text)-1] != ')') {
+		return fmt.Errorf("invalid range literal %q: expected brackets or parentheses", text)
+	}
+	lower, rest, err := parsePGRangeBound(text[1:len(text)-1], ',')
+	if err != nil {
+		return fmt.Errorf("invalid range literal %q: %w", text, err)
+	}
+	upper, rest, err := parsePGRangeBound(rest, 0)
+	if err != nil || rest != "" {
+		return fmt.Errorf("invalid range literal %q: expected two bounds", text)
+	}
+	rng := Range[T]{LowerInc: text[0] == '[', UpperInc: text[len(text)-1] == ']'}
+	for _, b := range []struct {
+		text *string
+		dst  *Nil[T]
+	}{{lower, &rng.Lower}, {upper, &rng.Upper}} {
+		if b.text == nil {
+			continue
+		}
+		if err := scanPGElem(reflect.ValueOf(&b.dst.Val).Elem(), pgArrayNode{text: b.text}); err != nil {
+			return fmt.Errorf("cannot scan Range bound: %w", err)
+		}
+		b.dst.NotNil = true
+	}
+	*r = rng
+	return nil
+}
+
+// Value implements the driver.Valuer interface, returning the range text format.
+func (r Range[T]) Value() (driver.Value, error) {
+	if r.IsEmpty() {
+		return "empty", nil
+	}
+	buf := []byte{'('}
+	if r.Lower.NotNil && r.LowerInc {
+		buf[0] = '['
+	}
+	var err error
+	if r.Lower.NotNil {
+		if buf, err = appendPGElem(buf, reflect.ValueOf(r.Lower.Val), appendPGRangeText); err != nil {
+			return nil, fmt.Errorf("cannot encode Range bound: %w", err)
+		}
+	}
+	buf = append(buf, ',')
+	if r.Upper.NotNil {
+		if buf, err = appendPGElem(buf, reflect.ValueOf(r.Upper.Val), appendPGRangeText); err != nil {
+			return nil, fmt.Errorf("cannot encode Range bound: %w", err)
+		}
+	}
+	if r.Upper.NotNil && r.UpperInc {
+		return string(append(buf, ']')), nil
+	}
+	return string(append(buf, ')')), nil
+}
+
+// rangeJSON is the JSON and BSON representation of a Range, where infinite bounds are null.
+type rangeJSON[T any] struct {
+	Lower    *T   `json:"lower" bson:"lower"`
+	Upper    *T   `json:"upper" bson:"upper"`
+	LowerInc bool `json:"lowerInc" bson:"lowerInc"`
+	UpperInc bool `json:"upperInc" bson:"upperInc"`
+	Empty    bool `json:"empty,omitempty" bson:"empty,omitempty"`
+}
+
+func (r Range[T]) toJSON() rangeJSON[T] {
+	if r.IsEmpty() {
+		return rangeJSON[T]{Empty: true}
+	}
+	return rangeJSON[T]{Lower: r.Lower.Ptr(), Upper: r.Upper.Ptr(), LowerInc: r.LowerInc && r.Lower.NotNil, UpperInc: r.UpperInc && r.Upper.NotNil}
+}
+
+func (r *Range[T]) fromJSON(v rangeJSON[T]) {
+	*r = Range[T]{Lower: NilFromPtr(v.Lower), Upper: NilFromPtr(v.Upper), LowerInc: v.LowerInc, UpperInc: v.UpperInc, Empty: v.Empty}
+}
+
+// MarshalJSON implements the json.Marshaler interface, e.g. {"lower":1,"upper":null,"lowerInc":true,"upperInc":false}.
+func (r Range[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.toJSON())
+}
+
+// jsonSchema implements the schemaProvider interface, describing the object encoded by MarshalJSON.
+func (r Range[T]) jsonSchema(of func(reflect.Type) (*Schema, error)) (*Schema, error) {
+	return of(reflect.TypeFor[rangeJSON[T]]())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *Range[T]) UnmarshalJSON(data []byte) error {
+	var v rangeJSON[T]
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.fromJSON(v)
+	return nil
+}
+
+// MarshalBSON implements the bson.Marshaler interface, using the same document shape as JSON.
+func (r Range[T]) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(r.toJSON())
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface.
+func (r *Range[T]) UnmarshalBSON(data []byte) error {
+	var v rangeJSON[T]
+	if err := bson.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.fromJSON(v)
+	return nil
+}
+
+// rangeBound is a lower or upper bound of a range, which is infinite unless finite is true.
+type rangeBound[T any] struct {
+	val    T
+	finite bool
+	inc    bool
+}
+
+func (r Range[T]) lower() rangeBound[T] {
+	return rangeBound[T]{val: r.Lower.Val, finite: r.Lower.NotNil, inc: r.LowerInc}
+}
+
+func (r Range[T]) upper() rangeBound[T] {
+	return rangeBound[T]{val: r.Upper.Val, finite: r.Upper.NotNil, inc: r.UpperInc}
+}
+
+func rangeOf[T any](lower, upper rangeBound[T]) Range[T] {
+	r := Range[T]{LowerInc: lower.inc, UpperInc: upper.inc}
+	if lower.finite {
+		r.Lower = NilFrom(lower.val)
+	}
+	if upper.finite {
+		r.Upper = NilFrom(upper.val)
+	}
+	return r
+}
+
+// boundsMeet reports whether there is a value above the lower bound and below the upper bound.
+func boundsMeet[T any](lower, upper rangeBound[T]) bool {
+	if !lower.finite || !upper.finite {
+		return true
+	}
+	c := compareValues(lower.val, upper.val)
+	return c < 0 || c == 0 && lower.inc && upper.inc
+}
+
+// compareLower orders lower bounds, where an infinite bound is the lowest.
+func compareLower[T any](a, b rangeBound[T]) int {
+	switch {
+	case !a.finite && !b.finite:
+		return 0
+	case !a.finite:
+		return -1
+	case !b.finite:
+		return 1
+	}
+	if c := compareValues(a.val, b.val); c != 0 {
+		return c
+	}
+	switch {
+	case a.inc == b.inc:
+		return 0
+	case a.inc:
+		return -1
+	}
+	return 1
+}
+
+// compareUpper orders upper bounds, where an infinite bound is the highest.
+func compareUpper[T any](a, b rangeBound[T]) int {
+	switch {
+	case !a.finite && !b.finite:
+		return 0
+	case !a.finite:
+		return 1
+	case !b.finite:
+		return -1
+	}
+	if c := compareValues(a.val, b.val); c != 0 {
+		return c
+	}
+	switch {
+	case a.inc == b.inc:
+		return 0
+	case a.inc:
+		return 1
+	}
+	return -1
+}
+
+// compareValues compares two values of an ordered kind or of a type with a Compare(T) int method.
+func compareValues[T any](a, b T) int {
+	if c, ok := any(a).(interface{ Compare(T) int }); ok {
+		return c.Compare(b)
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(va.Int(), vb.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(va.Uint(), vb.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(va.Float(), vb.Float())
+	case reflect.String:
+		return strings.Compare(va.String(), vb.String())
+	}
+	panic(fmt.Sprintf("typx: cannot compare values of type %T", a))
+}
+
+func compareOrdered[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// appendPGRangeText appends s, quoting and escaping it if needed.
+func appendPGRangeText(buf []byte, s string) []byte {
+	if s != "" && !strings.ContainsAny(s, "()[],\"\\ \t\n\r\v\f") {
+		return append(buf, s...)
+	}
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			buf = append(buf, '\\')
+		}
+		buf = append(buf, s[i])
+	}
+	return append(buf, '"')
+}
+
+// parsePGRangeBound parses a bound up to the delimiter (or the end if delim is 0) and returns the remaining text.
+// A nil bound means it is infinite.
+func parsePGRangeBound(s string, delim byte) (*string, string, error) {
+	var b strings.Builder
+	quoted, wasQuoted := false, false
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			if i+1 >= len(s) {
+				return nil, "", errors.New("unterminated escape")
+			}
+			i++
+			c = s[i]
+		case c == '"' && quoted && i+1 < len(s) && s[i+1] == '"':
+			i++
+		case c == '"':
+			quoted = !quoted
+			wasQuoted = true
+			continue
+		case c == delim && !quoted:
+			return pgRangeBoundText(b.String(), wasQuoted), s[i+1:], nil
+		}
+		b.WriteByte(c)
+	}
+	if quoted {
+		return nil, "", errors.New("unterminated quoted bound")
+	}
+	if delim != 0 {
+		return nil, "", fmt.Errorf("missing %q", delim)
+	}
+	return pgRangeBoundText(b.String(), wasQuoted), "", nil
+}
+
+// pgRangeBoundText returns the text of a bound, or nil if it is infinite (empty and unquoted).
+func pgRangeBoundText(text string, quoted bool) *string {
+	if !quoted {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil
+		}
+	}
+	return &text
+}
@@ -0,0 +1,152 @@
+package typx_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_Range_Scan(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  typx.Range[int]
+	}{
+		{name: "canonical", value: "[1,10)", want: typx.RangeFrom(1, 10)},
+		{name: "inclusive", value: "[1,10]", want: typx.Range[int]{Lower: typx.NilFrom(1), Upper: typx.NilFrom(10), LowerInc: true, UpperInc: true}},
+		{name: "infinite lower", value: "(,5]", want: typx.Range[int]{Upper: typx.NilFrom(5), UpperInc: true}},
+		{name: "infinite both", value: "(,)", want: typx.Range[int]{}},
+		{name: "quoted", value: `("1","2")`, want: typx.Range[int]{Lower: typx.NilFrom(1), Upper: typx.NilFrom(2)}},
+		{name: "empty", value: "empty", want: typx.EmptyRange[int]()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got typx.Range[int]
+			assert.NoError(t, got.Scan([]byte(tt.value)))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	var ts typx.Range[time.Time]
+	assert.NoError(t, ts.Scan(`["2024-01-01 10:00:00+00","2024-01-01 12:00:00+00")`))
+	assert.True(t, ts.Lower.Val.Equal(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)))
+	assert.True(t, ts.Upper.Val.Equal(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)))
+
+	var text typx.Range[string]
+	assert.NoError(t, text.Scan(`["a,b","c""d\\e"]`))
+	assert.Equal(t, typx.Range[string]{Lower: typx.NilFrom("a,b"), Upper: typx.NilFrom(`c"d\e`), LowerInc: true, UpperInc: true}, text)
+
+	for _, invalid := range []any{nil, 1, "", "[1,2", "[1]", "[1,2,3)", "[a,2)", `["1,2)`} {
+		var got typx.Range[int]
+		assert.Error(t, got.Scan(invalid), invalid)
+	}
+
+	var null typx.Nil[typx.Range[int]]
+	assert.NoError(t, null.Scan(nil))
+	assert.False(t, null.NotNil)
+}
+
+func Test_Range_Value(t *testing.T) {
+	tests := []struct {
+		name  string
+		value typx.Range[string]
+		want  string
+	}{
+		{name: "canonical", value: typx.RangeFrom("a", "b"), want: "[a,b)"},
+		{name: "infinite", value: typx.Range[string]{Upper: typx.NilFrom("b"), LowerInc: true, UpperInc: true}, want: "(,b]"},
+		{name: "quoting", value: typx.RangeFrom("a b", `c"(d`), want: `["a b","c\"(d")`},
+		{name: "empty", value: typx.EmptyRange[string](), want: "empty"},
+		{name: "empty bounds", value: typx.RangeFrom("b", "b"), want: "empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.value.Value()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			var back typx.Range[string]
+			assert.NoError(t, back.Scan(got))
+			assert.Equal(t, tt.value.IsEmpty(), back.IsEmpty())
+		})
+	}
+}
+
+func Test_Range_Operations(t *testing.T) {
+	r := typx.RangeFrom(1, 10)
+	assert.True(t, r.Contains(1))
+	assert.True(t, r.Contains(9))
+	assert.False(t, r.Contains(10))
+	assert.False(t, typx.EmptyRange[int]().Contains(1))
+	assert.True(t, typx.Range[int]{Upper: typx.NilFrom(0)}.Contains(-100))
+
+	assert.True(t, r.Overlaps(typx.RangeFrom(9, 20)))
+	assert.False(t, r.Overlaps(typx.RangeFrom(10, 20)))
+	assert.False(t, r.Overlaps(typx.EmptyRange[int]()))
+
+	assert.Equal(t, typx.RangeFrom(5, 10), r.Intersect(typx.Range[int]{Lower: typx.NilFrom(5), LowerInc: true}))
+	assert.True(t, r.Intersect(typx.RangeFrom(10, 20)).IsEmpty())
+
+	union, err := r.Union(typx.RangeFrom(10, 20))
+	assert.NoError(t, err)
+	assert.Equal(t, typx.RangeFrom(1, 20), union)
+
+	union, err = r.Union(typx.Range[int]{Lower: typx.NilFrom(5)})
+	assert.NoError(t, err)
+	assert.Equal(t, typx.Range[int]{Lower: typx.NilFrom(1), LowerInc: true}, union)
+
+	union, err = r.Union(typx.EmptyRange[int]())
+	assert.NoError(t, err)
+	assert.Equal(t, r, union)
+
+	_, err = r.Union(typx.RangeFrom(11, 20))
+	assert.Error(t, err)
+
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	booking := typx.RangeFrom(day(1), day(5))
+	assert.True(t, booking.Contains(day(4)))
+	assert.False(t, booking.Overlaps(typx.RangeFrom(day(5), day(8))))
+}
+
+func Test_Range_JSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		value typx.Range[int]
+		want  string
+	}{
+		{name: "bounded", value: typx.RangeFrom(1, 10), want: `{"lower":1,"upper":10,"lowerInc":true,"upperInc":false}`},
+		{name: "infinite", value: typx.Range[int]{Upper: typx.NilFrom(10), UpperInc: true}, want: `{"lower":null,"upper":10,"lowerInc":false,"upperInc":true}`},
+		{name: "empty", value: typx.EmptyRange[int](), want: `{"lower":null,"upper":null,"lowerInc":false,"upperInc":false,"empty":true}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+
+			var back typx.Range[int]
+			assert.NoError(t, json.Unmarshal(got, &back))
+			assert.Equal(t, tt.value, back)
+		})
+	}
+}
+
+func Test_Range_BSON(t *testing.T) {
+	type doc struct {
+		Validity typx.Range[int] `bson:"validity"`
+	}
+	value := doc{Validity: typx.Range[int]{Lower: typx.NilFrom(1), LowerInc: true}}
+	data, err := bson.Marshal(value)
+	assert.NoError(t, err)
+
+	var raw bson.M
+	assert.NoError(t, bson.Unmarshal(data, &raw))
+	assert.Equal(t, bson.M{"lower": int32(1), "upper": nil, "lowerInc": true, "upperInc": false}, raw["validity"])
+
+	var got doc
+	assert.NoError(t, bson.Unmarshal(data, &got))
+	assert.Equal(t, value, got)
+}
@@ -0,0 +1,94 @@
+package typx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var dynRegistry = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	byName: map[string]reflect.Type{},
+	byType: map[reflect.Type]string{},
+}
+
+// RegisterDynType registers T under the given name so that a Dyn holding a T round-trips
+// through the binary, text and gob encodings of Dyn, which then carry the type name in an envelope.
+// Envelopes of unregistered names are decoded into JSON-shaped trees instead.
+// Like gob.Register, it should be called during initialization and panics if
+// the name or the type is already registered differently.
+func RegisterDynType[T any](name string) {
+	t := reflect.TypeFor[T]()
+	dynRegistry.Lock()
+	defer dynRegistry.Unlock()
+	if existing, ok := dynRegistry.byName[name]; ok && existing != t {
+		panic(fmt.Sprintf("typx: registering duplicate types for %q: %s != %s", name, existing, t))
+	}
+	if existing, ok := dynRegistry.byType[t]; ok && existing != name {
+		panic(fmt.Sprintf("typx: registering duplicate names for %s: %q != %q", t, existing, name))
+	}
+	dynRegistry.byName[name] = t
+	dynRegistry.byType[t] = name
+}
+
+// dynTypeName returns the registered name of the type of v, if any.
+func dynTypeName(v any) (string, bool) {
+	if v == nil {
+		return "", false
+	}
+	dynRegistry.RLock()
+	defer dynRegistry.RUnlock()
+	name, ok := dynRegistry.byType[reflect.TypeOf(v)]
+	return name, ok
+}
+
+// dynEnvelope is the self-describing representation of a Dyn holding a registered type.
+type dynEnvelope struct {
+	Type  string          `json:"$type"`
+	Value json.RawMessage `json:"$value"`
+}
+
+var dynEnvelopePrefix = []byte(`{"$type":`)
+
+// marshalDynEnvelope encodes v, which must be of a registered type, along with its type name.
+func marshalDynEnvelope(name string, v any) ([]byte, error) {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(dynEnvelope{Type: name, Value: value})
+}
+
+// isDynEnvelope reports whether data looks like an envelope produced by marshalDynEnvelope.
+func isDynEnvelope(data []byte) bool {
+	return bytes.HasPrefix(data, dynEnvelopePrefix)
+}
+
+// unmarshalDynEnvelope decodes an envelope into a value of the registered type,
+// or into a JSON-shaped tree if the type name is not registered.
+func unmarshalDynEnvelope(data []byte) (any, error) {
+	var env dynEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	dynRegistry.RLock()
+	t, ok := dynRegistry.byName[env.Type]
+	dynRegistry.RUnlock()
+	if !ok {
+		var tree any
+		if err := json.Unmarshal(env.Value, &tree); err != nil {
+			return nil, err
+		}
+		return tree, nil
+	}
+	val := reflect.New(t)
+	if err := json.Unmarshal(env.Value, val.Interface()); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal Dyn envelope of type %q: %w", env.Type, err)
+	}
+	return val.Elem().Interface(), nil
+}
@@ -0,0 +1,135 @@
+package typx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Result is a type that holds either a value or an error, e.g. the outcome of one item of a batch.
+// It is an error if Err is not nil, in which case Val should be ignored.
+// It is encoded as JSON as {"value":...} or {"error":"..."}.
+type Result[T any] struct {
+	Val T
+	Err error
+}
+
+// ResultFrom creates a Result[T] from the return values of a function, e.g. ResultFrom(strconv.Atoi(s)).
+func ResultFrom[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Result[T]{Err: err}
+	}
+	return Result[T]{Val: value}
+}
+
+// ResultOk creates a Result[T] holding value.
+func ResultOk[T any](value T) Result[T] { return Result[T]{Val: value} }
+
+// ResultErr creates a Result[T] holding err, which must not be nil.
+func ResultErr[T any](err error) Result[T] { return Result[T]{Err: err} }
+
+// ResultMap applies f to the value of r, passing errors through.
+func ResultMap[T, U any](r Result[T], f func(T) U) Result[U] {
+	if r.Err != nil {
+		return Result[U]{Err: r.Err}
+	}
+	return Result[U]{Val: f(r.Val)}
+}
+
+// ResultAndThen applies the fallible f to the value of r, passing errors through.
+func ResultAndThen[T, U any](r Result[T], f func(T) (U, error)) Result[U] {
+	if r.Err != nil {
+		return Result[U]{Err: r.Err}
+	}
+	return ResultFrom(f(r.Val))
+}
+
+// IsOk reports whether the result holds a value.
+func (r Result[T]) IsOk() bool { return r.Err == nil }
+
+// Unwrap returns the value and the error, like the function the result was created from.
+func (r Result[T]) Unwrap() (T, error) {
+	if r.Err != nil {
+		return *new(T), r.Err
+	}
+	return r.Val, nil
+}
+
+// OrElse returns r if it holds a value, or the result of f applied to its error otherwise, e.g. to recover from it.
+func (r Result[T]) OrElse(f func(error) Result[T]) Result[T] {
+	if r.Err != nil {
+		return f(r.Err)
+	}
+	return r
+}
+
+// ToNil returns the value as a Nil[T], which is nil if the result holds an error.
+func (r Result[T]) ToNil() Nil[T] {
+	if r.Err != nil {
+		return Nil[T]{}
+	}
+	return NilFrom(r.Val)
+}
+
+// ToOpt returns the value as an Opt[T], which is unset if the result holds an error.
+func (r Result[T]) ToOpt() Opt[T] {
+	if r.Err != nil {
+		return Opt[T]{}
+	}
+	return OptFrom(r.Val)
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding {"value":...} or {"error":"..."}.
+func (r Result[T]) MarshalJSON() ([]byte, error) {
+	if r.Err != nil {
+		return json.Marshal(struct {
+			Error string `json:"error"`
+		}{r.Err.Error()})
+	}
+	return json.Marshal(struct {
+		Value T `json:"value"`
+	}{r.Val})
+}
+
+// jsonSchema implements the schemaProvider interface, describing either {"value":...} or {"error":"..."}.
+func (r Result[T]) jsonSchema(of func(reflect.Type) (*Schema, error)) (*Schema, error) {
+	value, err := of(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{AnyOf: []*Schema{
+		{Type: SchemaType{"object"}, Properties: map[string]*Schema{"value": value}, Required: []string{"value"}},
+		{Type: SchemaType{"object"}, Properties: map[string]*Schema{"error": {Type: SchemaType{"string"}}}, Required: []string{"error"}},
+	}}, nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Decoded errors only keep their message.
+func (r *Result[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var v struct {
+		Value json.RawMessage `json:"value"`
+		Error *string         `json:"error"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch {
+	case v.Error != nil && v.Value != nil:
+		return fmt.Errorf("cannot unmarshal %s into Result: expected either a value or an error", data)
+	case v.Error != nil:
+		*r = Result[T]{Err: errors.New(*v.Error)}
+		return nil
+	case v.Value == nil:
+		return fmt.Errorf("cannot unmarshal %s into Result: expected a value or an error", data)
+	}
+	var val T
+	if err := json.Unmarshal(v.Value, &val); err != nil {
+		return err
+	}
+	*r = Result[T]{Val: val}
+	return nil
+}
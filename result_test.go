@@ -0,0 +1,62 @@
+package typx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Result(t *testing.T) {
+	ok := typx.ResultFrom(strconv.Atoi("42"))
+	assert.True(t, ok.IsOk())
+	val, err := ok.Unwrap()
+	assert.NoError(t, err)
+	assert.Equal(t, 42, val)
+	assert.Equal(t, typx.NilFrom(42), ok.ToNil())
+	assert.Equal(t, typx.OptFrom(42), ok.ToOpt())
+
+	failed := typx.ResultFrom(strconv.Atoi("x"))
+	assert.False(t, failed.IsOk())
+	val, err = failed.Unwrap()
+	assert.Error(t, err)
+	assert.Equal(t, 0, val)
+	assert.Equal(t, typx.Nil[int]{}, failed.ToNil())
+	assert.Equal(t, typx.Opt[int]{}, failed.ToOpt())
+
+	assert.Equal(t, typx.ResultOk("42!"), typx.ResultMap(ok, func(v int) string { return strconv.Itoa(v) + "!" }))
+	assert.Equal(t, failed.Err, typx.ResultMap(failed, strconv.Itoa).Err)
+
+	assert.Equal(t, typx.ResultOk(43), typx.ResultAndThen(ok, func(v int) (int, error) { return v + 1, nil }))
+	boom := errors.New("boom")
+	assert.Equal(t, typx.ResultErr[int](boom), typx.ResultAndThen(ok, func(int) (int, error) { return 0, boom }))
+	assert.Equal(t, failed.Err, typx.ResultAndThen(failed, func(v int) (int, error) { return v, nil }).Err)
+
+	recovered := failed.OrElse(func(err error) typx.Result[int] { return typx.ResultOk(-1) })
+	assert.Equal(t, typx.ResultOk(-1), recovered)
+	assert.Equal(t, ok, ok.OrElse(func(error) typx.Result[int] { return typx.ResultOk(-1) }))
+}
+
+func Test_Result_JSON(t *testing.T) {
+	results := []typx.Result[typx.Nil[int]]{
+		typx.ResultOk(typx.NilFrom(1)),
+		typx.ResultOk(typx.Nil[int]{}),
+		typx.ResultErr[typx.Nil[int]](errors.New("not found")),
+	}
+	data, err := json.Marshal(results)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"value":1},{"value":null},{"error":"not found"}]`, string(data))
+
+	var got []typx.Result[typx.Nil[int]]
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, results[:2], got[:2])
+	assert.EqualError(t, got[2].Err, "not found")
+
+	var r typx.Result[int]
+	for _, invalid := range []string{`{}`, `{"value":1,"error":"x"}`, `{"value":"x"}`, `[]`} {
+		assert.Error(t, json.Unmarshal([]byte(invalid), &r), invalid)
+	}
+}
@@ -0,0 +1,113 @@
+package typx
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// optionalTarget is implemented by *Opt[T] so that it can be scanned into while tracking presence.
+type optionalTarget interface{ setTarget() any }
+
+// ScanRow scans the current row of rows into the struct pointed to by dst.
+// Columns are mapped to fields by their `db` tag or by the snake_case form of the field name,
+// and fields of untagged embedded structs are promoted. Fields tagged with `db:"-"` are ignored.
+// Each value is scanned with the usual database/sql conversions, so Nil and Dyn fields use their Scan methods.
+// Opt fields whose column is absent from the result set are left unset,
+// while a column without a matching field is an error.
+// The struct is reset before scanning, and rows.Next must have been called beforehand.
+func ScanRow(rows *sql.Rows, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot scan row into %T: expected a non-nil pointer to a struct", dst)
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	v = v.Elem()
+	fields := rowFieldsOf(v.Type())
+	v.SetZero()
+	targets := make([]any, len(columns))
+	for i, column := range columns {
+		index, ok := fields[column]
+		if !ok {
+			return fmt.Errorf("cannot scan column %q: no matching field in %s", column, v.Type())
+		}
+		target := v.FieldByIndex(index).Addr().Interface()
+		if opt, ok := target.(optionalTarget); ok {
+			target = opt.setTarget()
+		}
+		targets[i] = target
+	}
+	return rows.Scan(targets...)
+}
+
+// ScanAll scans all remaining rows into a slice of T, which must be a struct type, using ScanRow.
+// It closes rows when done.
+func ScanAll[T any](rows *sql.Rows) ([]T, error) {
+	defer rows.Close()
+	var result []T
+	for rows.Next() {
+		var item T
+		if err := ScanRow(rows, &item); err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var rowFieldCache sync.Map // map[reflect.Type]map[string][]int
+
+// rowFieldsOf maps column names to the field indexes of a struct type.
+func rowFieldsOf(t reflect.Type) map[string][]int {
+	if cached, ok := rowFieldCache.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+	fields := map[string][]int{}
+	for _, sf := range reflect.VisibleFields(t) {
+		if !sf.IsExported() || viaEmbeddedPointer(t, sf.Index) {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("db"), ",")
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			continue
+		}
+		if name == "" {
+			name = snakeCase(sf.Name)
+		}
+		if _, ok := fields[name]; !ok {
+			fields[name] = sf.Index
+		}
+	}
+	rowFieldCache.Store(t, fields)
+	return fields
+}
+
+// snakeCase converts a Go identifier such as "UserID" or "HTTPServer" into "user_id" or "http_server".
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])
+			if prevLower || nextLower {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,144 @@
+package typx_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeResults maps queries to the columns and rows returned by the fake driver.
+var fakeResults = map[string]struct {
+	columns []string
+	rows    [][]driver.Value
+}{
+	"full": {
+		columns: []string{"id", "user_name", "landline", "mobile", "fax", "additional_info", "created_by"},
+		rows: [][]driver.Value{
+			{int64(1), "alice", nil, "0912", "041", []byte(`{"age":30}`), "admin"},
+			{int64(2), "bob", "021", nil, "031", nil, "admin"},
+		},
+	},
+	"partial": {
+		columns: []string{"id", "user_name"},
+		rows:    [][]driver.Value{{int64(3), "carol"}},
+	},
+	"unknown": {
+		columns: []string{"id", "unknown"},
+		rows:    [][]driver.Value{{int64(4), "x"}},
+	},
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query: query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type fakeStmt struct{ query string }
+
+func (fakeStmt) Close() error                               { return nil }
+func (fakeStmt) NumInput() int                              { return 0 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) { return nil, errors.New("not supported") }
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	result := fakeResults[s.query]
+	return &fakeRows{columns: result.columns, rows: result.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("typxfake", fakeDriver{})
+}
+
+type rowAudit struct {
+	CreatedBy string
+}
+
+type rowUser struct {
+	ID             int64
+	Name           string                     `db:"user_name"`
+	Landline       typx.Nil[string]           `db:"landline"`
+	Mobile         typx.Opt[typx.Nil[string]] `db:"mobile"`
+	Fax            typx.Opt[string]           `db:"fax"`
+	AdditionalInfo typx.Opt[typx.Dyn]
+	Ignored        string `db:"-"`
+	rowAudit
+}
+
+func Test_ScanAll(t *testing.T) {
+	db, err := sql.Open("typxfake", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows, err := db.Query("full")
+	assert.NoError(t, err)
+	got, err := typx.ScanAll[rowUser](rows)
+	assert.NoError(t, err)
+	assert.Equal(t, []rowUser{
+		{
+			ID:             1,
+			Name:           "alice",
+			Mobile:         typx.OptFrom(typx.NilFrom("0912")),
+			Fax:            typx.OptFrom("041"),
+			AdditionalInfo: typx.OptFrom(typx.Dyn{Val: map[string]any{"age": float64(30)}}),
+			rowAudit:       rowAudit{CreatedBy: "admin"},
+		},
+		{
+			ID:             2,
+			Name:           "bob",
+			Landline:       typx.NilFrom("021"),
+			Mobile:         typx.OptFrom(typx.Nil[string]{}),
+			Fax:            typx.OptFrom("031"),
+			AdditionalInfo: typx.OptFrom(typx.Dyn{}),
+			rowAudit:       rowAudit{CreatedBy: "admin"},
+		},
+	}, got)
+
+	rows, err = db.Query("partial")
+	assert.NoError(t, err)
+	got, err = typx.ScanAll[rowUser](rows)
+	assert.NoError(t, err)
+	assert.Equal(t, []rowUser{{ID: 3, Name: "carol"}}, got)
+
+	rows, err = db.Query("unknown")
+	assert.NoError(t, err)
+	_, err = typx.ScanAll[rowUser](rows)
+	assert.ErrorContains(t, err, `"unknown"`)
+}
+
+func Test_ScanRow_InvalidDestination(t *testing.T) {
+	db, err := sql.Open("typxfake", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows, err := db.Query("partial")
+	assert.NoError(t, err)
+	defer rows.Close()
+	assert.True(t, rows.Next())
+	assert.Error(t, typx.ScanRow(rows, rowUser{}))
+	var id int64
+	assert.Error(t, typx.ScanRow(rows, &id))
+}
@@ -0,0 +1,369 @@
+package typx
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Schema is a JSON Schema (draft 2020-12), which is also an OpenAPI 3.1 schema object.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+	Type                 SchemaType         `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	WriteOnly            bool               `json:"writeOnly,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+// SchemaType is the type keyword of a Schema. It is encoded as a string if it has a single type.
+type SchemaType []string
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t SchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaType{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+var dynSchemaRegistry = struct {
+	sync.RWMutex
+	schemas map[string]*Schema
+}{schemas: map[string]*Schema{}}
+
+// RegisterDynSchema registers the schema of the Dyn fields tagged with `typx:"schema=name"`,
+// which are otherwise described by the empty schema that accepts any value.
+// Like RegisterDynType, it should be called during initialization and panics if the name is already registered.
+func RegisterDynSchema(name string, schema *Schema) {
+	dynSchemaRegistry.Lock()
+	defer dynSchemaRegistry.Unlock()
+	if _, ok := dynSchemaRegistry.schemas[name]; ok {
+		panic(fmt.Sprintf("typx: registering duplicate Dyn schema %q", name))
+	}
+	dynSchemaRegistry.schemas[name] = schema
+}
+
+// SchemaFor returns the JSON Schema of the JSON encoding of T, which can also be used as an OpenAPI 3.1 component.
+// Nil[T] is the schema of T that also allows null, Opt[T] is the schema of T as a property that is not required,
+// and Dyn is the empty schema or the one registered with RegisterDynSchema. Array, Range and Result are described
+// by the shapes they are encoded as, and Secret[T] is the schema of T marked writeOnly, since it is decoded from T
+// but encoded as Redacted.
+// Opt[T] is described as encoded by encoding/json/v2, which requires the jsonv2 experiment (the default since Go 1.27):
+// encoding/json (v1) keeps encoding it as {"val":...,"set":...}, which the returned schema does not describe.
+// Struct fields are named and omitted by their json tags, and fields with the omitempty or omitzero options
+// are not required either. Pointers allow null, and types implementing encoding.TextMarshaler are strings.
+// Recursive struct types are referenced with $ref to the $defs of the returned schema.
+func SchemaFor[T any]() (*Schema, error) {
+	t := reflect.TypeFor[T]()
+	b := schemaBuilder{root: t, building: map[reflect.Type]bool{}, recursive: map[reflect.Type]bool{}}
+	s, err := b.schemaOf(t, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(b.defs) > 0 {
+		s.Defs = b.defs
+	}
+	return s, nil
+}
+
+var (
+	dynType               = reflect.TypeFor[Dyn]()
+	uuidType              = reflect.TypeFor[uuid.UUID]()
+	textMarshalerType     = reflect.TypeFor[encoding.TextMarshaler]()
+	jsonMarshalerType     = reflect.TypeFor[json.Marshaler]()
+	nullableTargetType    = reflect.TypeFor[nullableTarget]()
+	optionalTargetType    = reflect.TypeFor[optionalTarget]()
+	enumNamesProviderType = reflect.TypeFor[interface{ Names() []string }]()
+	schemaProviderType    = reflect.TypeFor[schemaProvider]()
+)
+
+// schemaProvider is implemented by the types that describe their JSON encoding themselves,
+// such as Array and Result, given a function returning the schemas of other types like their type arguments.
+type schemaProvider interface {
+	jsonSchema(of func(reflect.Type) (*Schema, error)) (*Schema, error)
+}
+
+type schemaBuilder struct {
+	root      reflect.Type
+	building  map[reflect.Type]bool // the struct types whose schema is being built
+	recursive map[reflect.Type]bool // the struct types that refer to themselves
+	defs      map[string]*Schema
+}
+
+// schemaOf returns the schema of t, where dynSchema is the registered schema name of a Dyn field.
+func (b *schemaBuilder) schemaOf(t reflect.Type, dynSchema string) (*Schema, error) {
+	switch {
+	case t == dynType:
+		if dynSchema == "" {
+			return &Schema{}, nil
+		}
+		dynSchemaRegistry.RLock()
+		s, ok := dynSchemaRegistry.schemas[dynSchema]
+		dynSchemaRegistry.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("cannot generate schema for Dyn: schema %q is not registered", dynSchema)
+		}
+		return s, nil
+	case t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(nullableTargetType):
+		s, err := b.schemaOf(t.Field(0).Type, dynSchema)
+		if err != nil {
+			return nil, err
+		}
+		return nullableSchema(s), nil
+	case t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(optionalTargetType):
+		return b.schemaOf(t.Field(0).Type, dynSchema)
+	case t == timeType:
+		return &Schema{Type: SchemaType{"string"}, Format: "date-time"}, nil
+	case t == uuidType:
+		return &Schema{Type: SchemaType{"string"}, Format: "uuid"}, nil
+	case t.Implements(schemaProviderType):
+		return reflect.Zero(t).Interface().(schemaProvider).jsonSchema(func(t reflect.Type) (*Schema, error) {
+			return b.schemaOf(t, dynSchema)
+		})
+	case t.Implements(textMarshalerType):
+		s := &Schema{Type: SchemaType{"string"}}
+		if t.Implements(enumNamesProviderType) {
+			for _, name := range reflect.Zero(t).Interface().(interface{ Names() []string }).Names() {
+				s.Enum = append(s.Enum, name)
+			}
+		}
+		return s, nil
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: SchemaType{"boolean"}}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: SchemaType{"integer"}, Format: "int32"}, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: SchemaType{"integer"}, Format: "int64"}, nil
+	case reflect.Float32:
+		return &Schema{Type: SchemaType{"number"}, Format: "float"}, nil
+	case reflect.Float64:
+		return &Schema{Type: SchemaType{"number"}, Format: "double"}, nil
+	case reflect.String:
+		return &Schema{Type: SchemaType{"string"}}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Pointer:
+		s, err := b.schemaOf(t.Elem(), dynSchema)
+		if err != nil {
+			return nil, err
+		}
+		return nullableSchema(s), nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 && !reflect.PointerTo(t.Elem()).Implements(textMarshalerType) {
+			return &Schema{Type: SchemaType{"string"}, ContentEncoding: "base64"}, nil
+		}
+		items, err := b.schemaOf(t.Elem(), dynSchema)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: SchemaType{"array"}, Items: items}, nil
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !t.Key().Implements(textMarshalerType) {
+				return nil, fmt.Errorf("cannot generate schema for %s: expected map keys of a string, integer or encoding.TextMarshaler type", t)
+			}
+		}
+		values, err := b.schemaOf(t.Elem(), dynSchema)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: SchemaType{"object"}, AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return b.structSchema(t)
+	}
+	return nil, fmt.Errorf("cannot generate schema for %s: expected a type that can be encoded as JSON", t)
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) (*Schema, error) {
+	ref := "#"
+	if t != b.root {
+		ref = "#/$defs/" + schemaDefName(t)
+	}
+	if b.building[t] {
+		b.recursive[t] = true
+		return &Schema{Ref: ref}, nil
+	}
+	b.building[t] = true
+	defer delete(b.building, t)
+
+	s := &Schema{Type: SchemaType{"object"}, Properties: map[string]*Schema{}}
+	for _, f := range structFields(t, "json") {
+		sf, name, opts := f.StructField, f.name, f.opts
+		dynSchema := ""
+		for _, opt := range strings.Split(sf.Tag.Get("typx"), ",") {
+			if schema, ok := strings.CutPrefix(opt, "schema="); ok {
+				dynSchema = schema
+			}
+		}
+		prop, err := b.schemaOf(sf.Type, dynSchema)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of %s: %w", sf.Name, t, err)
+		}
+		if hasTagOption(opts, "string") && len(prop.Type) == 1 {
+			switch prop.Type[0] {
+			case "boolean", "integer", "number":
+				prop = &Schema{Type: SchemaType{"string"}}
+			}
+		}
+		s.Properties[name] = prop
+		optional := hasTagOption(opts, "omitempty") || hasTagOption(opts, "omitzero") || viaEmbeddedPointer(t, sf.Index) ||
+			(sf.Type.Kind() == reflect.Struct && reflect.PointerTo(sf.Type).Implements(optionalTargetType))
+		if !optional {
+			s.Required = append(s.Required, name)
+		}
+	}
+	if b.recursive[t] && t != b.root {
+		if b.defs == nil {
+			b.defs = map[string]*Schema{}
+		}
+		b.defs[schemaDefName(t)] = s
+		return &Schema{Ref: ref}, nil
+	}
+	return s, nil
+}
+
+// structField is a struct field as encoding/json sees it, where Index is the path from the outer struct.
+type structField struct {
+	reflect.StructField
+	name   string
+	opts   string
+	tagged bool
+}
+
+// structFields returns the fields of the struct type t named by the given tag (falling back to the json tag)
+// the way encoding/json does: the fields of untagged embedded structs and struct pointers are promoted,
+// tagged embedded structs are fields of their own, and of the fields sharing a name only the shallowest one
+// is kept, or the tagged one among the shallowest ones, while the others cancel each other out.
+func structFields(t reflect.Type, key string) []structField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	var fields []structField
+	current, visited := []embedded{{typ: t}}, map[reflect.Type]bool{}
+	for len(current) > 0 {
+		var next []embedded
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+			for i := range e.typ.NumField() {
+				sf := e.typ.Field(i)
+				ft := sf.Type
+				if sf.Anonymous && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if !sf.IsExported() && (!sf.Anonymous || ft.Kind() != reflect.Struct) {
+					continue
+				}
+				name, opts, tagged := fieldTag(sf, key)
+				if name == "-" && opts == "" {
+					continue
+				}
+				sf.Index = append(slices.Clone(e.index), i)
+				if sf.Anonymous && !tagged && ft.Kind() == reflect.Struct {
+					next = append(next, embedded{typ: ft, index: sf.Index})
+					continue
+				}
+				if name == "" {
+					name = sf.Name
+				}
+				fields = append(fields, structField{StructField: sf, name: name, opts: opts, tagged: tagged})
+			}
+		}
+		current = next
+	}
+
+	slices.SortStableFunc(fields, func(a, b structField) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := len(a.Index) - len(b.Index); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+	dominant := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if j-i == 1 || len(fields[i+1].Index) > len(fields[i].Index) || fields[i].tagged && !fields[i+1].tagged {
+			dominant = append(dominant, fields[i])
+		}
+		i = j
+	}
+	slices.SortFunc(dominant, func(a, b structField) int { return slices.Compare(a.Index, b.Index) })
+	return dominant
+}
+
+// nullableSchema returns a schema that allows null in addition to the values of s.
+func nullableSchema(s *Schema) *Schema {
+	switch {
+	case len(s.Type) > 0:
+		if slices.Contains(s.Type, "null") {
+			return s
+		}
+		nullable := *s
+		nullable.Type = append(slices.Clip(s.Type), "null")
+		if len(s.Enum) > 0 {
+			nullable.Enum = append(slices.Clip(s.Enum), nil)
+		}
+		return &nullable
+	case reflect.ValueOf(*s).IsZero():
+		return s // the empty schema already allows null
+	default:
+		return &Schema{AnyOf: []*Schema{s, {Type: SchemaType{"null"}}}}
+	}
+}
+
+// schemaDefName returns the name of t in $defs, replacing the characters of type arguments that are not
+// letters or digits, e.g. Tree_int_ for Tree[int].
+func schemaDefName(t reflect.Type) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, t.Name())
+}
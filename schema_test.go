@@ -0,0 +1,282 @@
+package typx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	typx.RegisterDynSchema("settings", &typx.Schema{
+		Type:                 typx.SchemaType{"object"},
+		AdditionalProperties: &typx.Schema{Type: typx.SchemaType{"string"}},
+	})
+}
+
+type schemaBase struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type schemaUser struct {
+	schemaBase
+	Name     string                       `json:"name"`
+	Landline typx.Nil[string]             `json:"landline"`
+	Email    typx.Opt[string]             `json:"email,omitzero"`
+	Phone    typx.Opt[typx.Nil[string]]   `json:"phone,omitzero"`
+	Info     typx.Dyn                     `json:"info"`
+	Settings typx.Nil[typx.Dyn]           `json:"settings" typx:"schema=settings"`
+	Color    typx.Enum[color]             `json:"color"`
+	Tags     []string                     `json:"tags,omitempty"`
+	Scores   map[string]float64           `json:"scores"`
+	Avatar   []byte                       `json:"avatar"`
+	Manager  *schemaUser                  `json:"manager"`
+	Age      int                          `json:"age,string"`
+	Ignored  string                       `json:"-"`
+	Opts     typx.Opt[typx.Nil[typx.Dyn]] `json:",omitzero"`
+	internal string
+}
+
+func Test_SchemaFor(t *testing.T) {
+	s, err := typx.SchemaFor[schemaUser]()
+	require.NoError(t, err)
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"createdAt": {"type": "string", "format": "date-time"},
+			"name": {"type": "string"},
+			"landline": {"type": ["string", "null"]},
+			"email": {"type": "string"},
+			"phone": {"type": ["string", "null"]},
+			"info": {},
+			"settings": {"type": ["object", "null"], "additionalProperties": {"type": "string"}},
+			"color": {"type": "string", "enum": ["red", "green"]},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"scores": {"type": "object", "additionalProperties": {"type": "number", "format": "double"}},
+			"avatar": {"type": "string", "contentEncoding": "base64"},
+			"manager": {"anyOf": [{"$ref": "#"}, {"type": "null"}]},
+			"age": {"type": "string"},
+			"Opts": {}
+		},
+		"required": ["id", "createdAt", "name", "landline", "info", "settings", "color", "scores", "avatar", "manager", "age"]
+	}`, string(data))
+}
+
+type schemaNode struct {
+	Value    typx.Nil[int] `json:"value"`
+	Children []schemaNode  `json:"children"`
+}
+
+func Test_SchemaFor_Defs(t *testing.T) {
+	s, err := typx.SchemaFor[[]typx.Nil[schemaNode]]()
+	require.NoError(t, err)
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "array",
+		"items": {"anyOf": [{"$ref": "#/$defs/schemaNode"}, {"type": "null"}]},
+		"$defs": {
+			"schemaNode": {
+				"type": "object",
+				"properties": {
+					"value": {"type": ["integer", "null"], "format": "int64"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/schemaNode"}}
+				},
+				"required": ["value", "children"]
+			}
+		}
+	}`, string(data))
+
+	var decoded typx.Schema
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, s, &decoded)
+}
+
+func Test_SchemaFor_Errors(t *testing.T) {
+	_, err := typx.SchemaFor[chan int]()
+	assert.Error(t, err)
+	_, err = typx.SchemaFor[map[[2]int]string]()
+	assert.Error(t, err)
+	_, err = typx.SchemaFor[struct {
+		Val typx.Dyn `typx:"schema=missing"`
+	}]()
+	assert.Error(t, err)
+	assert.Panics(t, func() { typx.RegisterDynSchema("settings", &typx.Schema{}) })
+}
+
+type schemaAudit struct {
+	ID     string `json:"id"`
+	Author string `json:"author"`
+}
+
+type schemaStamp struct {
+	CreatedAt string `json:"createdAt"`
+	Title     string `json:"title"`
+	Edited    bool
+}
+
+type schemaPost struct {
+	schemaAudit `json:"audit"`
+	*schemaBase
+	schemaStamp
+	Title string `json:"title"`
+}
+
+func Test_SchemaFor_Embedded(t *testing.T) {
+	s, err := typx.SchemaFor[schemaPost]()
+	require.NoError(t, err)
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"audit": {
+				"type": "object",
+				"properties": {"id": {"type": "string"}, "author": {"type": "string"}},
+				"required": ["id", "author"]
+			},
+			"id": {"type": "string", "format": "uuid"},
+			"Edited": {"type": "boolean"},
+			"title": {"type": "string"}
+		},
+		"required": ["audit", "Edited", "title"]
+	}`, string(data))
+
+	encoded, err := json.Marshal(schemaPost{schemaBase: &schemaBase{}})
+	require.NoError(t, err)
+	var object map[string]any
+	require.NoError(t, json.Unmarshal(encoded, &object))
+	assert.ElementsMatch(t, slices.Collect(maps.Keys(s.Properties)), slices.Collect(maps.Keys(object)))
+}
+
+type schemaTypes struct {
+	Scores typx.Array[typx.Nil[int]] `json:"scores"`
+	Tags   typx.Array[string]        `json:"tags"`
+	Period typx.Range[int]           `json:"period"`
+	Empty  typx.Range[int]           `json:"empty"`
+	Done   typx.Result[int]          `json:"done"`
+	Failed typx.Result[int]          `json:"failed"`
+	Key    typx.Secret[string]       `json:"key"`
+}
+
+func Test_SchemaFor_Types(t *testing.T) {
+	s, err := typx.SchemaFor[schemaTypes]()
+	require.NoError(t, err)
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	rangeSchema := `{
+		"type": "object",
+		"properties": {
+			"lower": {"type": ["integer", "null"], "format": "int64"},
+			"upper": {"type": ["integer", "null"], "format": "int64"},
+			"lowerInc": {"type": "boolean"},
+			"upperInc": {"type": "boolean"},
+			"empty": {"type": "boolean"}
+		},
+		"required": ["lower", "upper", "lowerInc", "upperInc"]
+	}`
+	resultSchema := `{"anyOf": [
+		{"type": "object", "properties": {"value": {"type": "integer", "format": "int64"}}, "required": ["value"]},
+		{"type": "object", "properties": {"error": {"type": "string"}}, "required": ["error"]}
+	]}`
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"scores": {"type": ["array", "null"], "items": {"type": ["integer", "null"], "format": "int64"}},
+			"tags": {"type": ["array", "null"], "items": {"type": "string"}},
+			"period": `+rangeSchema+`,
+			"empty": `+rangeSchema+`,
+			"done": `+resultSchema+`,
+			"failed": `+resultSchema+`,
+			"key": {"type": "string", "writeOnly": true}
+		},
+		"required": ["scores", "tags", "period", "empty", "done", "failed", "key"]
+	}`, string(data))
+
+	encoded, err := json.Marshal(schemaTypes{
+		Scores: typx.Array[typx.Nil[int]]{typx.NilFrom(1), {}},
+		Period: typx.Range[int]{Lower: typx.NilFrom(1), LowerInc: true},
+		Empty:  typx.Range[int]{Empty: true},
+		Done:   typx.ResultFrom(1, nil),
+		Failed: typx.ResultFrom(0, errors.New("failed")),
+		Key:    typx.SecretFrom("sk-123"),
+	})
+	require.NoError(t, err)
+	var value any
+	require.NoError(t, json.Unmarshal(encoded, &value))
+	assert.True(t, schemaAccepts(s, s, value), string(encoded))
+	assert.False(t, schemaAccepts(s, s, map[string]any{"scores": []any{"a"}}))
+}
+
+// schemaAccepts reports whether the decoded JSON value v is valid against s,
+// supporting the keywords that SchemaFor generates only.
+func schemaAccepts(root, s *typx.Schema, v any) bool {
+	switch {
+	case s.Ref == "#":
+		return schemaAccepts(root, root, v)
+	case strings.HasPrefix(s.Ref, "#/$defs/"):
+		return schemaAccepts(root, root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")], v)
+	case len(s.AnyOf) > 0:
+		return slices.ContainsFunc(s.AnyOf, func(s *typx.Schema) bool { return schemaAccepts(root, s, v) })
+	}
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(typ string) bool { return schemaTypeOf(v, typ) }) {
+		return false
+	}
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, v) {
+		return false
+	}
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			if s.Items != nil && !schemaAccepts(root, s.Items, item) {
+				return false
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return false
+			}
+		}
+		for name, item := range v {
+			if prop, ok := s.Properties[name]; ok && !schemaAccepts(root, prop, item) {
+				return false
+			}
+			if _, ok := s.Properties[name]; !ok && s.AdditionalProperties != nil && !schemaAccepts(root, s.AdditionalProperties, item) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func schemaTypeOf(v any, typ string) bool {
+	switch v := v.(type) {
+	case nil:
+		return typ == "null"
+	case bool:
+		return typ == "boolean"
+	case float64:
+		return typ == "number" || (typ == "integer" && v == math.Trunc(v))
+	case string:
+		return typ == "string"
+	case []any:
+		return typ == "array"
+	case map[string]any:
+		return typ == "object"
+	}
+	return false
+}
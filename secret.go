@@ -0,0 +1,148 @@
+package typx
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Redacted is the placeholder printed and encoded in place of secret values.
+const Redacted = "[REDACTED]"
+
+// Secret holds a sensitive value such as an API key, a token or PII.
+// It is printed as Redacted by fmt verbs (including %+v and %#v), String, GoString and slog,
+// and is encoded as Redacted in JSON (use StrictSecret to make it fail instead). SQL and BSON store and load the real value.
+// The value is kept behind a pointer so that it is not printed even inside unexported struct fields,
+// which means copies share it and == compares identity rather than the value.
+type Secret[T any] struct {
+	val *T
+}
+
+// SecretFrom creates a Secret[T] holding value.
+func SecretFrom[T any](value T) Secret[T] { return Secret[T]{val: &value} }
+
+// Reveal returns the secret value, or the zero value if none was set.
+func (s Secret[T]) Reveal() T {
+	if s.val == nil {
+		return *new(T)
+	}
+	return *s.val
+}
+
+// String implements the fmt.Stringer interface, returning Redacted.
+func (s Secret[T]) String() string { return Redacted }
+
+// GoString implements the fmt.GoStringer interface, returning Redacted.
+func (s Secret[T]) GoString() string { return Redacted }
+
+// Format implements the fmt.Formatter interface so that every verb prints Redacted.
+func (s Secret[T]) Format(f fmt.State, _ rune) { _, _ = io.WriteString(f, Redacted) }
+
+// LogValue implements the slog.LogValuer interface, returning Redacted.
+func (s Secret[T]) LogValue() slog.Value { return slog.StringValue(Redacted) }
+
+// Scan implements the sql.Scanner interface.
+// Errors only mention types so that the scanned value does not leak through them.
+func (s *Secret[T]) Scan(src any) error {
+	var val T
+	if scanner, ok := any(&val).(sql.Scanner); ok {
+		if err := scanner.Scan(src); err != nil {
+			return fmt.Errorf("cannot scan %T into Secret[%T]", src, val)
+		}
+		s.val = &val
+		return nil
+	}
+	switch v := src.(type) {
+	case T:
+		s.val = &v
+		return nil
+	case []byte:
+		if val, ok := any(string(v)).(T); ok {
+			s.val = &val
+			return nil
+		}
+	case string:
+		if val, ok := any([]byte(v)).(T); ok {
+			s.val = &val
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot scan %T into Secret[%T]", src, val)
+}
+
+// Value implements the driver.Valuer interface, returning the real value.
+func (s Secret[T]) Value() (driver.Value, error) {
+	val := s.Reveal()
+	if valuer, ok := any(val).(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return val, nil
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the Redacted string.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// jsonSchema implements the schemaProvider interface, describing T as writeOnly,
+// since secrets are decoded from T but encoded as Redacted.
+func (s Secret[T]) jsonSchema(of func(reflect.Type) (*Schema, error)) (*Schema, error) {
+	schema, err := of(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	withWriteOnly := *schema
+	withWriteOnly.WriteOnly = true
+	return &withWriteOnly, nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, loading the real value, e.g. from requests or configuration.
+// Errors only mention types so that the decoded value does not leak through them.
+func (s *Secret[T]) UnmarshalJSON(data []byte) error {
+	var val T
+	if err := json.Unmarshal(data, &val); err != nil {
+		return fmt.Errorf("cannot unmarshal JSON into Secret[%T]", val)
+	}
+	s.val = &val
+	return nil
+}
+
+// MarshalBSONValue implements the bson.ValueMarshaler interface, encoding the real value.
+func (s Secret[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(s.Reveal())
+}
+
+// UnmarshalBSONValue implements the bson.ValueUnmarshaler interface.
+// Errors only mention types so that the decoded value does not leak through them.
+func (s *Secret[T]) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	var val T
+	if err := bson.UnmarshalValue(t, data, &val); err != nil {
+		return fmt.Errorf("cannot unmarshal BSON %s into Secret[%T]", t, val)
+	}
+	s.val = &val
+	return nil
+}
+
+// StrictSecret is a Secret that fails to encode as JSON instead of encoding the Redacted string,
+// so that redacted values cannot end up in stored JSON. It behaves like Secret otherwise.
+type StrictSecret[T any] struct {
+	Secret[T]
+}
+
+// StrictSecretFrom creates a StrictSecret[T] holding value.
+func StrictSecretFrom[T any](value T) StrictSecret[T] {
+	return StrictSecret[T]{Secret: SecretFrom(value)}
+}
+
+// MarshalJSON implements the json.Marshaler interface, always returning an error.
+func (s StrictSecret[T]) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("cannot marshal StrictSecret into JSON: secrets are not encodable")
+}
@@ -0,0 +1,107 @@
+package typx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_Secret_Redaction(t *testing.T) {
+	key := typx.SecretFrom("sk-123")
+	assert.Equal(t, "sk-123", key.Reveal())
+	assert.Equal(t, "", typx.Secret[string]{}.Reveal())
+
+	wrapped := struct {
+		Key    typx.Secret[string]
+		hidden typx.Secret[string]
+		Token  typx.Nil[typx.Secret[string]]
+	}{Key: key, hidden: key, Token: typx.NilFrom(key)}
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d"} {
+		assert.NotContains(t, fmt.Sprintf(format, key), "sk-123", format)
+		assert.NotContains(t, fmt.Sprintf(format, wrapped), "sk-123", format)
+		assert.NotContains(t, fmt.Sprintf(format, &wrapped), "sk-123", format)
+	}
+	assert.Equal(t, typx.Redacted, fmt.Sprintf("%v", key))
+	assert.Equal(t, typx.Redacted, key.String())
+	assert.Equal(t, typx.Redacted, key.GoString())
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("login", "key", key, "wrapped", wrapped)
+	assert.NotContains(t, buf.String(), "sk-123")
+	assert.Contains(t, buf.String(), `"key":"[REDACTED]"`)
+}
+
+func Test_Secret_JSON(t *testing.T) {
+	type doc struct {
+		Key   typx.Secret[string]           `json:"key"`
+		Token typx.Nil[typx.Secret[string]] `json:"token"`
+	}
+	value := doc{Key: typx.SecretFrom("sk-123"), Token: typx.NilFrom(typx.SecretFrom("tok"))}
+	data, err := json.Marshal(value)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"key":"[REDACTED]","token":"[REDACTED]"}`, string(data))
+
+	_, err = json.Marshal(struct{ Key typx.StrictSecret[string] }{typx.StrictSecretFrom("sk-123")})
+	assert.Error(t, err)
+
+	var got doc
+	assert.NoError(t, json.Unmarshal([]byte(`{"key":"sk-123","token":null}`), &got))
+	assert.Equal(t, "sk-123", got.Key.Reveal())
+	assert.False(t, got.Token.NotNil)
+
+	var strict struct{ Key typx.StrictSecret[string] }
+	assert.NoError(t, json.Unmarshal([]byte(`{"Key":"sk-123"}`), &strict))
+	assert.Equal(t, "sk-123", strict.Key.Reveal())
+	assert.Equal(t, typx.Redacted, fmt.Sprintf("%+v", strict.Key))
+
+	var number typx.Secret[int]
+	err = json.Unmarshal([]byte(`"sk-123"`), &number)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "sk-123")
+}
+
+func Test_Secret_SQL(t *testing.T) {
+	var got typx.Nil[typx.Secret[string]]
+	assert.NoError(t, got.Scan([]byte("sk-123")))
+	assert.True(t, got.NotNil)
+	assert.Equal(t, "sk-123", got.Val.Reveal())
+
+	value, err := got.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-123", value)
+
+	assert.NoError(t, got.Scan(nil))
+	assert.False(t, got.NotNil)
+
+	var number typx.Secret[int64]
+	err = number.Scan("sk-123")
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "sk-123")
+
+	var nilNumber typx.Nil[int64]
+	err = nilNumber.Scan("sk-123")
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "sk-123")
+}
+
+func Test_Secret_BSON(t *testing.T) {
+	type doc struct {
+		Key typx.Secret[string] `bson:"key"`
+	}
+	data, err := bson.Marshal(doc{Key: typx.SecretFrom("sk-123")})
+	assert.NoError(t, err)
+
+	var raw bson.M
+	assert.NoError(t, bson.Unmarshal(data, &raw))
+	assert.Equal(t, "sk-123", raw["key"])
+
+	var got doc
+	assert.NoError(t, bson.Unmarshal(data, &got))
+	assert.Equal(t, "sk-123", got.Key.Reveal())
+}
@@ -0,0 +1,96 @@
+// Package typxsort sorts typx.Nil and typx.Opt values in memory with the same null ordering as
+// SQL's ORDER BY ... NULLS FIRST/LAST, and provides comparators compatible with slices.SortFunc.
+//
+// Sorts are stable. For descending orders, note that PostgreSQL defaults to NULLS FIRST for DESC,
+// which is Reverse(NilsLast[T]) since reversing a comparator also reverses where nulls go.
+package typxsort
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/pedramktb/go-typx"
+)
+
+// SortNilsFirst sorts s in ascending order with nil values first.
+func SortNilsFirst[T cmp.Ordered](s []typx.Nil[T]) {
+	slices.SortStableFunc(s, NilsFirst[T])
+}
+
+// SortNilsLast sorts s in ascending order with nil values last.
+func SortNilsLast[T cmp.Ordered](s []typx.Nil[T]) {
+	slices.SortStableFunc(s, NilsLast[T])
+}
+
+// SortNilsFirstFunc sorts s in the order of compare with nil values first.
+func SortNilsFirstFunc[T any](s []typx.Nil[T], compare func(T, T) int) {
+	slices.SortStableFunc(s, NilsFirstFunc(compare))
+}
+
+// SortNilsLastFunc sorts s in the order of compare with nil values last.
+func SortNilsLastFunc[T any](s []typx.Nil[T], compare func(T, T) int) {
+	slices.SortStableFunc(s, NilsLastFunc(compare))
+}
+
+// NilsFirst compares a and b in ascending order with nil values first.
+func NilsFirst[T cmp.Ordered](a, b typx.Nil[T]) int { return typx.NilCompare(a, b, true) }
+
+// NilsLast compares a and b in ascending order with nil values last.
+func NilsLast[T cmp.Ordered](a, b typx.Nil[T]) int { return typx.NilCompare(a, b, false) }
+
+// NilsFirstFunc returns a comparator in the order of compare with nil values first.
+func NilsFirstFunc[T any](compare func(T, T) int) func(a, b typx.Nil[T]) int {
+	return func(a, b typx.Nil[T]) int { return typx.NilCompareFunc(a, b, true, compare) }
+}
+
+// NilsLastFunc returns a comparator in the order of compare with nil values last.
+func NilsLastFunc[T any](compare func(T, T) int) func(a, b typx.Nil[T]) int {
+	return func(a, b typx.Nil[T]) int { return typx.NilCompareFunc(a, b, false, compare) }
+}
+
+// SortUnsetFirst sorts s in ascending order with unset values first.
+func SortUnsetFirst[T cmp.Ordered](s []typx.Opt[T]) {
+	slices.SortStableFunc(s, UnsetFirst[T])
+}
+
+// SortUnsetLast sorts s in ascending order with unset values last.
+func SortUnsetLast[T cmp.Ordered](s []typx.Opt[T]) {
+	slices.SortStableFunc(s, UnsetLast[T])
+}
+
+// UnsetFirst compares a and b in ascending order with unset values first.
+func UnsetFirst[T cmp.Ordered](a, b typx.Opt[T]) int { return typx.OptCompare(a, b, true) }
+
+// UnsetLast compares a and b in ascending order with unset values last.
+func UnsetLast[T cmp.Ordered](a, b typx.Opt[T]) int { return typx.OptCompare(a, b, false) }
+
+// UnsetFirstFunc returns a comparator in the order of compare with unset values first.
+func UnsetFirstFunc[T any](compare func(T, T) int) func(a, b typx.Opt[T]) int {
+	return func(a, b typx.Opt[T]) int { return typx.OptCompareFunc(a, b, true, compare) }
+}
+
+// UnsetLastFunc returns a comparator in the order of compare with unset values last.
+func UnsetLastFunc[T any](compare func(T, T) int) func(a, b typx.Opt[T]) int {
+	return func(a, b typx.Opt[T]) int { return typx.OptCompareFunc(a, b, false, compare) }
+}
+
+// By returns a comparator of rows that compares the keys extracted from them with compare,
+// e.g. By(func(u User) typx.Nil[string] { return u.Email }, NilsLast[string]).
+func By[S, K any](key func(S) K, compare func(K, K) int) func(a, b S) int {
+	return func(a, b S) int { return compare(key(a), key(b)) }
+}
+
+// Reverse returns a comparator in the reverse order of compare, including where nil or unset values go.
+func Reverse[S any](compare func(a, b S) int) func(a, b S) int {
+	return func(a, b S) int { return compare(b, a) }
+}
+
+// Then returns a comparator that orders by compare and breaks ties with next, like ORDER BY a, b.
+func Then[S any](compare, next func(a, b S) int) func(a, b S) int {
+	return func(a, b S) int {
+		if c := compare(a, b); c != 0 {
+			return c
+		}
+		return next(a, b)
+	}
+}
@@ -0,0 +1,78 @@
+package typxsort_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/pedramktb/go-typx/typxsort"
+	"github.com/stretchr/testify/assert"
+)
+
+func nils(values ...*int) []typx.Nil[int] {
+	s := make([]typx.Nil[int], len(values))
+	for i, v := range values {
+		s[i] = typx.NilFromPtr(v)
+	}
+	return s
+}
+
+func ptr(v int) *int { return &v }
+
+func Test_SortNils(t *testing.T) {
+	s := nils(ptr(3), nil, ptr(1), nil, ptr(2))
+	typxsort.SortNilsFirst(s)
+	assert.Equal(t, nils(nil, nil, ptr(1), ptr(2), ptr(3)), s)
+
+	typxsort.SortNilsLast(s)
+	assert.Equal(t, nils(ptr(1), ptr(2), ptr(3), nil, nil), s)
+
+	slices.SortFunc(s, typxsort.Reverse(typxsort.NilsLast[int]))
+	assert.Equal(t, nils(nil, nil, ptr(3), ptr(2), ptr(1)), s)
+
+	words := []typx.Nil[string]{typx.NilFrom("b"), {}, typx.NilFrom("A")}
+	typxsort.SortNilsLastFunc(words, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+	assert.Equal(t, []typx.Nil[string]{typx.NilFrom("A"), typx.NilFrom("b"), {}}, words)
+
+	typxsort.SortNilsFirstFunc(words, strings.Compare)
+	assert.Equal(t, []typx.Nil[string]{{}, typx.NilFrom("A"), typx.NilFrom("b")}, words)
+}
+
+func Test_SortUnset(t *testing.T) {
+	s := []typx.Opt[int]{typx.OptFrom(2), {}, typx.OptFrom(1)}
+	typxsort.SortUnsetFirst(s)
+	assert.Equal(t, []typx.Opt[int]{{}, typx.OptFrom(1), typx.OptFrom(2)}, s)
+
+	typxsort.SortUnsetLast(s)
+	assert.Equal(t, []typx.Opt[int]{typx.OptFrom(1), typx.OptFrom(2), {}}, s)
+
+	slices.SortFunc(s, typxsort.UnsetFirstFunc(func(a, b int) int { return b - a }))
+	assert.Equal(t, []typx.Opt[int]{{}, typx.OptFrom(2), typx.OptFrom(1)}, s)
+
+	slices.SortFunc(s, typxsort.UnsetLastFunc(func(a, b int) int { return a - b }))
+	assert.Equal(t, []typx.Opt[int]{typx.OptFrom(1), typx.OptFrom(2), {}}, s)
+}
+
+func Test_SortRows(t *testing.T) {
+	type row struct {
+		Name  string
+		Email typx.Nil[string]
+	}
+	rows := []row{
+		{Name: "b", Email: typx.NilFrom("x@example.com")},
+		{Name: "a"},
+		{Name: "c", Email: typx.NilFrom("x@example.com")},
+		{Name: "d", Email: typx.NilFrom("a@example.com")},
+	}
+	// ORDER BY email DESC NULLS LAST, name
+	slices.SortFunc(rows, typxsort.Then(
+		typxsort.By(func(r row) typx.Nil[string] { return r.Email }, typxsort.Reverse(typxsort.NilsFirst[string])),
+		typxsort.By(func(r row) string { return r.Name }, strings.Compare),
+	))
+	names := make([]string, len(rows))
+	for i, r := range rows {
+		names[i] = r.Name
+	}
+	assert.Equal(t, []string{"b", "c", "d", "a"}, names)
+}
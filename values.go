@@ -0,0 +1,509 @@
+package typx
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ValuesCodec decodes and encodes url.Values like DecodeValues and EncodeValues, which use its zero value.
+type ValuesCodec struct {
+	// Null is the value that decodes into a nil Nil[T] in addition to an empty value.
+	// Nil values are encoded as it, so it can be set to a token such as "null"
+	// to tell nil values apart from empty strings.
+	Null string
+}
+
+type valuesField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var valuesFieldCache sync.Map // map[reflect.Type][]valuesField
+
+// valuesFieldsOf returns the fields of a struct type that are decoded from and encoded to url.Values,
+// promoting the fields of untagged embedded structs and struct pointers like encoding/json.
+func valuesFieldsOf(t reflect.Type) []valuesField {
+	if cached, ok := valuesFieldCache.Load(t); ok {
+		return cached.([]valuesField)
+	}
+	var fields []valuesField
+	for _, sf := range structFields(t, "form") {
+		fields = append(fields, valuesField{name: sf.name, index: sf.Index, omitEmpty: hasTagOption(sf.opts, "omitempty")})
+	}
+	valuesFieldCache.Store(t, fields)
+	return fields
+}
+
+// isValuesStruct reports whether t is a struct whose fields are decoded from and encoded to bracket-nested keys,
+// such as address[city], rather than a single value.
+func isValuesStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != dynType && !reflect.PointerTo(t).Implements(optionalTargetType) &&
+		!reflect.PointerTo(t).Implements(nullableTargetType) && !reflect.PointerTo(t).Implements(textUnmarshalerType) &&
+		!t.Implements(textMarshalerType)
+}
+
+// DecodeValues decodes url.Values, such as a URL query or a parsed form, into the struct pointed to by dst.
+// Fields are named by their `form` tag, falling back to their `json` tag and their name.
+// Opt fields are set only if their key is present, and Nil fields are nil if their value is empty.
+// Slices are decoded from repeated keys, optionally suffixed with [], and Dyn fields from bracket-nested keys,
+// such as filter[a][b]=1, into trees of maps, slices and strings. Struct fields, including tagged embedded structs,
+// are decoded from the bracket-nested keys of their own fields, such as address[city], and the fields of untagged
+// embedded structs are promoted like in encoding/json, allocating nil embedded pointers if any of their keys are present. Other values are parsed with their
+// encoding.TextUnmarshaler implementation or as strings, numbers and booleans.
+// Fields whose keys are absent are left as they are.
+func DecodeValues(values url.Values, dst any) error {
+	return ValuesCodec{}.Decode(values, dst)
+}
+
+// Decode is like DecodeValues, and also decodes c.Null into nil Nil fields.
+func (c ValuesCodec) Decode(values url.Values, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot decode values into %T: expected a non-nil pointer to a struct", dst)
+	}
+	if err := c.decodeValuesFields(values, "", v.Elem()); err != nil {
+		return fmt.Errorf("cannot decode values into %s: %w", v.Elem().Type(), err)
+	}
+	return nil
+}
+
+// decodeValuesFields decodes the fields of the struct v, whose keys are nested in prefix unless it is empty.
+func (c ValuesCodec) decodeValuesFields(values url.Values, prefix string, v reflect.Value) error {
+	for _, f := range valuesFieldsOf(v.Type()) {
+		key := f.name
+		if prefix != "" {
+			key = prefix + "[" + f.name + "]"
+		}
+		field, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// The field is promoted through a nil embedded pointer, which is only allocated if the key is present.
+			if !valuesHas(values, key) {
+				continue
+			}
+			if field, err = fieldByIndexAlloc(v, f.index); err != nil {
+				return err
+			}
+		}
+		if err := c.decodeValuesKey(values, key, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// valuesHas reports whether key is present in values, on its own, with [] or with nested keys.
+func valuesHas(values url.Values, key string) bool {
+	if values.Has(key) || values.Has(key+"[]") {
+		return true
+	}
+	for k := range values {
+		if strings.HasPrefix(k, key+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeValuesKey decodes the values of key into v.
+func (c ValuesCodec) decodeValuesKey(values url.Values, key string, v reflect.Value) error {
+	t := v.Type()
+	switch {
+	case t == dynType:
+		if !valuesHas(values, key) {
+			return nil
+		}
+		tree, err := valuesTree(values, key)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(Dyn{Val: tree}))
+		return nil
+	case t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(optionalTargetType):
+		if !valuesHas(values, key) {
+			return nil
+		}
+		v.SetZero()
+		if err := c.decodeValuesKey(values, key, v.Field(0)); err != nil {
+			return err
+		}
+		v.Field(1).SetBool(true)
+		return nil
+	case t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(nullableTargetType):
+		if !valuesHas(values, key) {
+			return nil
+		}
+		v.SetZero()
+		if vals := values[key]; len(vals) == 1 && c.isValuesNull(vals[0]) && !values.Has(key+"[]") {
+			return nil
+		}
+		if err := c.decodeValuesKey(values, key, v.Field(0)); err != nil {
+			return err
+		}
+		v.Field(1).SetBool(true)
+		return nil
+	case isValuesStruct(t):
+		return c.decodeValuesFields(values, key, v)
+	case t.Kind() == reflect.Pointer && isValuesStruct(t.Elem()):
+		if !valuesHas(values, key) {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return c.decodeValuesFields(values, key, v.Elem())
+	case t.Kind() == reflect.Slice && !reflect.PointerTo(t).Implements(textUnmarshalerType) && t.Elem().Kind() != reflect.Uint8:
+		vals := append(slices.Clip(values[key]), values[key+"[]"]...)
+		if len(vals) == 0 {
+			return nil
+		}
+		s := reflect.MakeSlice(t, len(vals), len(vals))
+		for i, val := range vals {
+			if err := c.decodeValue(val, s.Index(i)); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	vals := values[key]
+	if len(vals) == 0 {
+		return nil
+	}
+	if err := c.decodeValue(vals[0], v); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+func (c ValuesCodec) isValuesNull(val string) bool {
+	return val == "" || val == c.Null
+}
+
+// decodeValue decodes a single value into v.
+func (c ValuesCodec) decodeValue(val string, v reflect.Value) error {
+	t := v.Type()
+	switch {
+	case t == dynType:
+		v.Set(reflect.ValueOf(Dyn{Val: val}))
+		return nil
+	case t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(optionalTargetType):
+		v.SetZero()
+		if err := c.decodeValue(val, v.Field(0)); err != nil {
+			return err
+		}
+		v.Field(1).SetBool(true)
+		return nil
+	case t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(nullableTargetType):
+		v.SetZero()
+		if c.isValuesNull(val) {
+			return nil
+		}
+		if err := c.decodeValue(val, v.Field(0)); err != nil {
+			return err
+		}
+		v.Field(1).SetBool(true)
+		return nil
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val))
+	}
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(val)
+	case reflect.Slice:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("cannot decode %q into %s: expected a scalar, Opt, Nil or Dyn", val, t)
+		}
+		v.SetBytes([]byte(val))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(val, 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(val, 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Pointer:
+		ptr := reflect.New(t.Elem())
+		if err := c.decodeValue(val, ptr.Elem()); err != nil {
+			return err
+		}
+		v.Set(ptr)
+	default:
+		return fmt.Errorf("cannot decode %q into %s: expected a scalar, slice, Opt, Nil or Dyn", val, t)
+	}
+	return nil
+}
+
+// valuesTree decodes key and its bracket-nested keys into a tree of maps, slices and strings,
+// where repeated keys and keys ending with [] are slices.
+func valuesTree(values url.Values, key string) (any, error) {
+	var root any
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if k == key || strings.HasPrefix(k, key+"[") {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		path, isSlice, err := parseValuesKey(k[len(key):])
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode key %q into Dyn: %w", k, err)
+		}
+		vals := values[k]
+		if len(vals) == 0 {
+			continue
+		}
+		var leaf any
+		if isSlice || len(vals) > 1 {
+			items := make([]any, len(vals))
+			for i, val := range vals {
+				items[i] = val
+			}
+			leaf = items
+		} else {
+			leaf = vals[0]
+		}
+		if root, err = setValuesTree(root, path, leaf); err != nil {
+			return nil, fmt.Errorf("cannot decode key %q into Dyn: %w", k, err)
+		}
+	}
+	return root, nil
+}
+
+// parseValuesKey parses the bracketed segments of a key, such as [a][b][], into a path.
+func parseValuesKey(s string) (path []string, isSlice bool, err error) {
+	for s != "" {
+		if s[0] != '[' {
+			return nil, false, errors.New("expected [")
+		}
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return nil, false, errors.New("expected ]")
+		}
+		segment := s[1:end]
+		s = s[end+1:]
+		if segment == "" {
+			if s != "" {
+				return nil, false, errors.New("expected [] at the end")
+			}
+			return path, true, nil
+		}
+		path = append(path, segment)
+	}
+	return path, false, nil
+}
+
+func setValuesTree(node any, path []string, leaf any) (any, error) {
+	if len(path) == 0 {
+		if node != nil {
+			return nil, errors.New("conflicting keys")
+		}
+		return leaf, nil
+	}
+	if node == nil {
+		node = map[string]any{}
+	}
+	m, ok := node.(map[string]any)
+	if !ok {
+		return nil, errors.New("conflicting keys")
+	}
+	child, err := setValuesTree(m[path[0]], path[1:], leaf)
+	if err != nil {
+		return nil, err
+	}
+	m[path[0]] = child
+	return m, nil
+}
+
+// EncodeValues encodes the struct src, or the struct it points to, into url.Values, inversely to DecodeValues.
+// Unset Opt fields and fields with the omitempty option and a zero value are omitted,
+// and nil Nil fields are encoded as empty values. Slices are encoded as repeated keys,
+// and Dyn and struct fields as bracket-nested keys, where Dyn slices can only hold scalars and nil struct pointers,
+// including embedded ones, are omitted.
+func EncodeValues(src any) (url.Values, error) {
+	return ValuesCodec{}.Encode(src)
+}
+
+// Encode is like EncodeValues, and encodes nil Nil fields as c.Null.
+func (c ValuesCodec) Encode(src any) (url.Values, error) {
+	v := reflect.ValueOf(src)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot encode %T into values: expected a struct or a non-nil pointer to a struct", src)
+	}
+	values := url.Values{}
+	if err := c.encodeValuesFields(values, "", v); err != nil {
+		return nil, fmt.Errorf("cannot encode %s into values: %w", v.Type(), err)
+	}
+	return values, nil
+}
+
+// encodeValuesFields adds the fields of the struct v, whose keys are nested in prefix unless it is empty.
+func (c ValuesCodec) encodeValuesFields(values url.Values, prefix string, v reflect.Value) error {
+	for _, f := range valuesFieldsOf(v.Type()) {
+		field, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			continue // the field is promoted through a nil embedded pointer
+		}
+		if f.omitEmpty && field.IsZero() {
+			continue
+		}
+		key := f.name
+		if prefix != "" {
+			key = prefix + "[" + f.name + "]"
+		}
+		if err := c.encodeValuesKey(values, key, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeValuesKey adds the values of v under key.
+func (c ValuesCodec) encodeValuesKey(values url.Values, key string, v reflect.Value) error {
+	t := v.Type()
+	switch {
+	case t == dynType:
+		return c.encodeValuesTree(values, key, v.Interface().(Dyn).Val)
+	case t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(optionalTargetType):
+		if !v.Field(1).Bool() {
+			return nil
+		}
+		return c.encodeValuesKey(values, key, v.Field(0))
+	case t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(nullableTargetType):
+		if !v.Field(1).Bool() {
+			values.Add(key, c.Null)
+			return nil
+		}
+		return c.encodeValuesKey(values, key, v.Field(0))
+	case isValuesStruct(t):
+		return c.encodeValuesFields(values, key, v)
+	case t.Kind() == reflect.Pointer && isValuesStruct(t.Elem()):
+		if v.IsNil() {
+			return nil
+		}
+		return c.encodeValuesFields(values, key, v.Elem())
+	case t.Kind() == reflect.Slice && !t.Implements(textMarshalerType) && t.Elem().Kind() != reflect.Uint8:
+		for i := range v.Len() {
+			val, err := c.encodeValue(v.Index(i))
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			values.Add(key, val)
+		}
+		return nil
+	}
+	val, err := c.encodeValue(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	values.Add(key, val)
+	return nil
+}
+
+// encodeValue encodes v as a single value.
+func (c ValuesCodec) encodeValue(v reflect.Value) (string, error) {
+	t := v.Type()
+	switch {
+	case t == dynType:
+		return c.encodeValuesScalar(v.Interface().(Dyn).Val)
+	case t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(optionalTargetType):
+		if !v.Field(1).Bool() {
+			return c.Null, nil
+		}
+		return c.encodeValue(v.Field(0))
+	case t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(nullableTargetType):
+		if !v.Field(1).Bool() {
+			return c.Null, nil
+		}
+		return c.encodeValue(v.Field(0))
+	case t.Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes()), nil
+		}
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, t.Bits()), nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return c.Null, nil
+		}
+		return c.encodeValue(v.Elem())
+	}
+	return "", fmt.Errorf("cannot encode %s: expected a scalar, slice, Opt, Nil or Dyn", t)
+}
+
+// encodeValuesTree adds a tree of maps, slices and scalars under key and its bracket-nested keys.
+func (c ValuesCodec) encodeValuesTree(values url.Values, key string, tree any) error {
+	switch node := tree.(type) {
+	case map[string]any:
+		for _, k := range slices.Sorted(maps.Keys(node)) {
+			if err := c.encodeValuesTree(values, key+"["+k+"]", node[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		for _, item := range node {
+			val, err := c.encodeValuesScalar(item)
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			values.Add(key+"[]", val)
+		}
+		return nil
+	}
+	val, err := c.encodeValuesScalar(tree)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	values.Add(key, val)
+	return nil
+}
+
+func (c ValuesCodec) encodeValuesScalar(val any) (string, error) {
+	if val == nil {
+		return c.Null, nil
+	}
+	return c.encodeValue(reflect.ValueOf(val))
+}
@@ -0,0 +1,181 @@
+package typx_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type valuesPage struct {
+	Limit int `form:"limit"`
+}
+
+type valuesFilter struct {
+	valuesPage
+	Query   typx.Opt[string]           `form:"q"`
+	Status  typx.Opt[typx.Nil[string]] `form:"status"`
+	Owner   typx.Nil[int64]            `json:"owner"`
+	Since   typx.Nil[time.Time]        `form:"since"`
+	IDs     []int                      `form:"id"`
+	Tags    typx.Opt[[]string]         `form:"tag"`
+	Filter  typx.Dyn                   `form:"filter"`
+	Color   typx.Enum[color]           `form:"color,omitempty"`
+	Ratio   float64                    `form:"ratio,omitempty"`
+	Ignored string                     `form:"-"`
+}
+
+func Test_DecodeValues(t *testing.T) {
+	values, err := url.ParseQuery("limit=10&q=&status=&owner=7&since=2024-01-02T03:04:05Z&id=1&id=2&tag[]=a" +
+		"&filter[a][b]=1&filter[a][c]=2&filter[d][]=x&filter[e]=y&filter[e]=z&color=red&Ignored=x")
+	require.NoError(t, err)
+	var f valuesFilter
+	require.NoError(t, typx.DecodeValues(values, &f))
+	assert.Equal(t, valuesFilter{
+		valuesPage: valuesPage{Limit: 10},
+		Query:      typx.OptFrom(""),
+		Status:     typx.OptFrom(typx.Nil[string]{}),
+		Owner:      typx.NilFrom[int64](7),
+		Since:      typx.NilFrom(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		IDs:        []int{1, 2},
+		Tags:       typx.OptFrom([]string{"a"}),
+		Filter: typx.Dyn{Val: map[string]any{
+			"a": map[string]any{"b": "1", "c": "2"},
+			"d": []any{"x"},
+			"e": []any{"y", "z"},
+		}},
+		Color: typx.Enum[color]{Val: "red"},
+	}, f)
+
+	f = valuesFilter{}
+	require.NoError(t, typx.DecodeValues(url.Values{"status": {"active"}, "filter": {"x"}}, &f))
+	assert.Equal(t, valuesFilter{Status: typx.OptFrom(typx.NilFrom("active")), Filter: typx.Dyn{Val: "x"}}, f)
+
+	codec := typx.ValuesCodec{Null: "null"}
+	f = valuesFilter{}
+	require.NoError(t, codec.Decode(url.Values{"status": {"null"}, "owner": {"null"}}, &f))
+	assert.Equal(t, valuesFilter{Status: typx.OptFrom(typx.Nil[string]{})}, f)
+	values, err = codec.Encode(f)
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"limit": {"0"}, "status": {"null"}, "owner": {"null"}, "since": {"null"}, "filter": {"null"}}, values)
+}
+
+func Test_DecodeValues_Errors(t *testing.T) {
+	var f valuesFilter
+	assert.Error(t, typx.DecodeValues(url.Values{}, f))
+	assert.Error(t, typx.DecodeValues(url.Values{}, (*valuesFilter)(nil)))
+	assert.Error(t, typx.DecodeValues(url.Values{"limit": {"x"}}, &f))
+	assert.Error(t, typx.DecodeValues(url.Values{"id": {"1", "x"}}, &f))
+	assert.Error(t, typx.DecodeValues(url.Values{"color": {"blue"}}, &f))
+	assert.Error(t, typx.DecodeValues(url.Values{"filter[a]": {"1"}, "filter[a][b]": {"2"}}, &f))
+	assert.Error(t, typx.DecodeValues(url.Values{"filter[a": {"1"}}, &f))
+	assert.Error(t, typx.DecodeValues(url.Values{"filter[][a]": {"1"}}, &f))
+	assert.Error(t, typx.DecodeValues(url.Values{"m": {"1"}}, &struct {
+		M map[string]string `form:"m"`
+	}{}))
+}
+
+func Test_EncodeValues(t *testing.T) {
+	f := valuesFilter{
+		valuesPage: valuesPage{Limit: 10},
+		Status:     typx.OptFrom(typx.Nil[string]{}),
+		Since:      typx.NilFrom(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		IDs:        []int{1, 2},
+		Tags:       typx.OptFrom([]string{"a"}),
+		Filter: typx.Dyn{Val: map[string]any{
+			"a": map[string]any{"b": "1", "c": 2.5},
+			"d": []any{"x", true},
+		}},
+	}
+	values, err := typx.EncodeValues(&f)
+	require.NoError(t, err)
+	assert.Equal(t, "filter%5Ba%5D%5Bb%5D=1&filter%5Ba%5D%5Bc%5D=2.5&filter%5Bd%5D%5B%5D=x&filter%5Bd%5D%5B%5D=true"+
+		"&id=1&id=2&limit=10&owner=&since=2024-01-02T03%3A04%3A05Z&status=&tag=a", values.Encode())
+
+	var decoded valuesFilter
+	require.NoError(t, typx.DecodeValues(values, &decoded))
+	f.Filter = typx.Dyn{Val: map[string]any{
+		"a": map[string]any{"b": "1", "c": "2.5"},
+		"d": []any{"x", "true"},
+	}}
+	assert.Equal(t, f, decoded)
+
+	_, err = typx.EncodeValues(1)
+	assert.Error(t, err)
+	_, err = typx.EncodeValues(valuesFilter{Filter: typx.Dyn{Val: []any{[]any{1}}}})
+	assert.Error(t, err)
+}
+
+type valuesAddress struct {
+	City string           `form:"city"`
+	Zip  typx.Opt[string] `form:"zip"`
+}
+
+type valuesOrder struct {
+	valuesPage `form:"page"`
+	Address    valuesAddress  `form:"address"`
+	Billing    *valuesAddress `form:"billing"`
+}
+
+func Test_Values_NestedStructs(t *testing.T) {
+	o := valuesOrder{valuesPage: valuesPage{Limit: 10}, Address: valuesAddress{City: "Berlin", Zip: typx.OptFrom("10115")}}
+	values, err := typx.EncodeValues(o)
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"page[limit]": {"10"}, "address[city]": {"Berlin"}, "address[zip]": {"10115"}}, values)
+
+	var decoded valuesOrder
+	require.NoError(t, typx.DecodeValues(values, &decoded))
+	assert.Equal(t, o, decoded)
+
+	decoded = valuesOrder{}
+	require.NoError(t, typx.DecodeValues(url.Values{"limit": {"10"}, "billing[city]": {"Paris"}}, &decoded))
+	assert.Equal(t, valuesOrder{Billing: &valuesAddress{City: "Paris"}}, decoded)
+}
+
+type valuesSort struct {
+	By string `form:"by"`
+}
+
+type valuesSearch struct {
+	*valuesPage
+	*valuesSort
+	Query string `form:"q"`
+}
+
+type ValuesSort struct {
+	By string `form:"by"`
+}
+
+type valuesExportedSearch struct {
+	*ValuesSort
+	Query string `form:"q"`
+}
+
+func Test_Values_EmbeddedPointers(t *testing.T) {
+	var s valuesExportedSearch
+	require.NoError(t, typx.DecodeValues(url.Values{"q": {"x"}}, &s))
+	assert.Equal(t, valuesExportedSearch{Query: "x"}, s)
+	require.NoError(t, typx.DecodeValues(url.Values{"by": {"name"}}, &s))
+	assert.Equal(t, valuesExportedSearch{ValuesSort: &ValuesSort{By: "name"}, Query: "x"}, s)
+
+	values, err := typx.EncodeValues(s)
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"by": {"name"}, "q": {"x"}}, values)
+	values, err = typx.EncodeValues(valuesExportedSearch{Query: "x"})
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"q": {"x"}}, values)
+
+	// Like encoding/json, pointers to unexported structs cannot be allocated, but can be encoded and decoded into.
+	var unexported valuesSearch
+	require.NoError(t, typx.DecodeValues(url.Values{"q": {"x"}}, &unexported))
+	assert.Error(t, typx.DecodeValues(url.Values{"limit": {"10"}}, &unexported))
+	unexported = valuesSearch{valuesPage: &valuesPage{}, valuesSort: &valuesSort{By: "name"}}
+	require.NoError(t, typx.DecodeValues(url.Values{"limit": {"10"}}, &unexported))
+	assert.Equal(t, 10, unexported.Limit)
+	values, err = typx.EncodeValues(unexported)
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"limit": {"10"}, "by": {"name"}, "q": {""}}, values)
+}
@@ -0,0 +1,261 @@
+package typx
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// XMLSchemaInstance is the namespace of the xsi:nil attribute used for nil values.
+const XMLSchemaInstance = "http://www.w3.org/2001/XMLSchema-instance"
+
+// xsiNil returns start with the xsi:nil="true" attribute (and its namespace declaration) added.
+func xsiNil(start xml.StartElement) xml.StartElement {
+	start.Attr = append(slices.Clone(start.Attr),
+		xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: XMLSchemaInstance},
+		xml.Attr{Name: xml.Name{Local: "xsi:nil"}, Value: "true"},
+	)
+	return start
+}
+
+// isXSINil reports whether the element carries xsi:nil="true", with or without a declared namespace.
+func isXSINil(start xml.StartElement) bool {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "nil" && (attr.Name.Space == XMLSchemaInstance || attr.Name.Space == "xsi") {
+			return attr.Value == "true" || attr.Value == "1"
+		}
+	}
+	return false
+}
+
+// writeXSINil encodes an empty element with xsi:nil="true".
+func writeXSINil(e *xml.Encoder, start xml.StartElement) error {
+	start = xsiNil(start)
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// marshalXMLAttrValue formats v as an attribute value the same way encoding/xml does for basic types.
+func marshalXMLAttrValue(v any) (string, error) {
+	switch val := v.(type) {
+	case encoding.TextMarshaler:
+		text, err := val.MarshalText()
+		return string(text), err
+	case []byte:
+		return string(val), nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("cannot marshal %T as XML attribute: expected encoding.TextMarshaler or a basic type", v)
+}
+
+// unmarshalXMLAttrValue parses an attribute value into the value pointed to by ptr.
+func unmarshalXMLAttrValue(ptr any, value string) error {
+	switch val := ptr.(type) {
+	case encoding.TextUnmarshaler:
+		return val.UnmarshalText([]byte(value))
+	case *[]byte:
+		*val = []byte(value)
+		return nil
+	}
+	rv := reflect.ValueOf(ptr).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(value)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		rv.SetBool(b)
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(strings.TrimSpace(value), 10, rv.Type().Bits())
+		rv.SetInt(i)
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(strings.TrimSpace(value), 10, rv.Type().Bits())
+		rv.SetUint(u)
+		return err
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), rv.Type().Bits())
+		rv.SetFloat(f)
+		return err
+	}
+	return fmt.Errorf("cannot unmarshal XML attribute into %s: expected encoding.TextUnmarshaler or a basic type", rv.Type())
+}
+
+// Dyn XML element convention. See Dyn.MarshalXML.
+const (
+	dynXMLTypeAttr = "type"
+	dynXMLItem     = "item"
+	dynXMLObject   = "object"
+	dynXMLArray    = "array"
+	dynXMLNumber   = "number"
+	dynXMLBoolean  = "boolean"
+)
+
+// dynJSONTree converts v to its JSON-shaped tree, keeping numbers as json.Number.
+func dynJSONTree(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// marshalDynXML encodes a JSON-shaped tree (as produced with json.Decoder.UseNumber) as XML.
+func marshalDynXML(e *xml.Encoder, start xml.StartElement, v any) error {
+	typ := ""
+	switch v.(type) {
+	case nil:
+		return writeXSINil(e, start)
+	case map[string]any:
+		typ = dynXMLObject
+	case []any:
+		typ = dynXMLArray
+	case json.Number:
+		typ = dynXMLNumber
+	case bool:
+		typ = dynXMLBoolean
+	}
+	if typ != "" {
+		start.Attr = append(slices.Clone(start.Attr), xml.Attr{Name: xml.Name{Local: dynXMLTypeAttr}, Value: typ})
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			if !isXMLName(k) {
+				return fmt.Errorf("cannot marshal Dyn as XML: key %q is not a valid XML element name", k)
+			}
+			if err := marshalDynXML(e, xml.StartElement{Name: xml.Name{Local: k}}, val[k]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range val {
+			if err := marshalDynXML(e, xml.StartElement{Name: xml.Name{Local: dynXMLItem}}, item); err != nil {
+				return err
+			}
+		}
+	case json.Number:
+		if err := e.EncodeToken(xml.CharData(val)); err != nil {
+			return err
+		}
+	case bool:
+		if err := e.EncodeToken(xml.CharData(strconv.FormatBool(val))); err != nil {
+			return err
+		}
+	case string:
+		if err := e.EncodeToken(xml.CharData(val)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot marshal %T in Dyn as XML", v)
+	}
+	return e.EncodeToken(start.End())
+}
+
+// unmarshalDynXML decodes the element started by start into a JSON-shaped tree.
+func unmarshalDynXML(d *xml.Decoder, start xml.StartElement) (any, error) {
+	if isXSINil(start) {
+		return nil, d.Skip()
+	}
+	typ := ""
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "" && attr.Name.Local == dynXMLTypeAttr {
+			typ = attr.Value
+		}
+	}
+	var (
+		text     bytes.Buffer
+		object   = map[string]any{}
+		array    = []any{}
+		children bool
+	)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			children = true
+			child, err := unmarshalDynXML(d, t)
+			if err != nil {
+				return nil, err
+			}
+			if typ == dynXMLArray {
+				array = append(array, child)
+			} else {
+				object[t.Name.Local] = child
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			switch typ {
+			case dynXMLObject:
+				return object, nil
+			case dynXMLArray:
+				return array, nil
+			case dynXMLNumber:
+				return strconv.ParseFloat(strings.TrimSpace(text.String()), 64)
+			case dynXMLBoolean:
+				return strconv.ParseBool(strings.TrimSpace(text.String()))
+			case "":
+				if children {
+					return object, nil
+				}
+				return text.String(), nil
+			}
+			return nil, fmt.Errorf("cannot unmarshal XML into Dyn: unknown type %q", typ)
+		}
+	}
+}
+
+// isXMLName reports whether s can be used as an unprefixed XML element name.
+func isXMLName(s string) bool {
+	if s == "" || strings.HasPrefix(strings.ToLower(s), "xml") {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r > 0x7f:
+		case i > 0 && (r == '-' || r == '.' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
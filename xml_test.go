@@ -0,0 +1,126 @@
+package typx_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+)
+
+type xmlUser struct {
+	XMLName  xml.Name                   `xml:"user"`
+	ID       typx.Nil[int]              `xml:"id,attr"`
+	Version  typx.Opt[int]              `xml:"version,attr"`
+	Name     typx.Opt[string]           `xml:"name"`
+	Landline typx.Nil[string]           `xml:"landline"`
+	Fax      typx.Opt[typx.Nil[string]] `xml:"fax"`
+	Info     typx.Dyn                   `xml:"info"`
+}
+
+func Test_XML_Marshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value xmlUser
+		want  string
+	}{
+		{
+			name:  "empty",
+			value: xmlUser{},
+			want: `<user><landline xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></landline>` +
+				`<info xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></info></user>`,
+		},
+		{
+			name: "full",
+			value: xmlUser{
+				ID:       typx.NilFrom(7),
+				Version:  typx.OptFrom(2),
+				Name:     typx.OptFrom("name"),
+				Landline: typx.NilFrom("123"),
+				Fax:      typx.OptFrom(typx.Nil[string]{}),
+				Info:     typx.Dyn{Val: map[string]any{"tags": []any{"a", 1.5}, "ok": true, "note": "x<y"}},
+			},
+			want: `<user id="7" version="2"><name>name</name><landline>123</landline>` +
+				`<fax xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></fax>` +
+				`<info type="object"><note>x&lt;y</note><ok type="boolean">true</ok>` +
+				`<tags type="array"><item>a</item><item type="number">1.5</item></tags></info></user>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := xml.Marshal(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	_, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"x"`
+		Info    typx.Dyn `xml:"info"`
+	}{Info: typx.Dyn{Val: map[string]any{"not valid": 1}}})
+	assert.Error(t, err)
+}
+
+func Test_XML_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  xmlUser
+	}{
+		{
+			name:  "absent",
+			value: `<user></user>`,
+			want:  xmlUser{XMLName: xml.Name{Local: "user"}},
+		},
+		{
+			name: "nil",
+			value: `<user xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">` +
+				`<landline xsi:nil="true"/><fax xsi:nil="true"/><info xsi:nil="true"/></user>`,
+			want: xmlUser{XMLName: xml.Name{Local: "user"}, Fax: typx.OptFrom(typx.Nil[string]{})},
+		},
+		{
+			name: "values",
+			value: `<user id="7" version="2"><name>name</name><landline>123</landline><fax>456</fax>` +
+				`<info type="object"><tags type="array"><item>a</item><item type="number">1.5</item></tags>` +
+				`<ok type="boolean">true</ok><nested><x>y</x></nested></info></user>`,
+			want: xmlUser{
+				XMLName:  xml.Name{Local: "user"},
+				ID:       typx.NilFrom(7),
+				Version:  typx.OptFrom(2),
+				Name:     typx.OptFrom("name"),
+				Landline: typx.NilFrom("123"),
+				Fax:      typx.OptFrom(typx.NilFrom("456")),
+				Info: typx.Dyn{Val: map[string]any{
+					"tags":   []any{"a", 1.5},
+					"ok":     true,
+					"nested": map[string]any{"x": "y"},
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got xmlUser
+			assert.NoError(t, xml.Unmarshal([]byte(tt.value), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	var invalid xmlUser
+	assert.Error(t, xml.Unmarshal([]byte(`<user id="abc"></user>`), &invalid))
+	assert.False(t, invalid.ID.NotNil)
+}
+
+func Test_Dyn_XML_Attr(t *testing.T) {
+	type tagged struct {
+		XMLName xml.Name `xml:"x"`
+		Meta    typx.Dyn `xml:"meta,attr"`
+	}
+	data, err := xml.Marshal(tagged{Meta: typx.Dyn{Val: map[string]any{"a": 1}}})
+	assert.NoError(t, err)
+	assert.Equal(t, `<x meta="{&#34;a&#34;:1}"></x>`, string(data))
+
+	var got tagged
+	assert.NoError(t, xml.Unmarshal(data, &got))
+	assert.Equal(t, typx.Dyn{Val: map[string]any{"a": float64(1)}}, got.Meta)
+}
@@ -0,0 +1,281 @@
+package typx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnmarshalYAML decodes the YAML document in data into v like yaml.Unmarshal, and then applies the null values
+// that yaml.v3 never passes to unmarshalers: a null resets a Nil or Dyn and marks an Opt as set,
+// so that `fax: ~` decodes into a set Opt[Nil[T]] holding nil while an absent key leaves it unset.
+// Nulls are applied through struct fields, Opt and Nil values, pointers, slices, arrays and maps.
+func UnmarshalYAML(data []byte, v any) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	return DecodeYAML(&node, v)
+}
+
+// DecodeYAML is like UnmarshalYAML for a node, e.g. one read by a yaml.Decoder.
+func DecodeYAML(node *yaml.Node, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("cannot unmarshal YAML into %T: expected a non-nil pointer", v)
+	}
+	if err := node.Decode(v); err != nil {
+		return err
+	}
+	applyYAMLNulls(node, rv.Elem())
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (n Nil[T]) MarshalYAML() (any, error) {
+	if !n.NotNil {
+		return nil, nil
+	}
+	return n.Val, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// Note that yaml.v3 does not call it for null nodes (~ or null) and leaves the value untouched instead,
+// use the UnmarshalYAML function to decode them as nil.
+func (n *Nil[T]) UnmarshalYAML(value *yaml.Node) error {
+	n.NotNil = false
+	if value.ShortTag() == "!!null" {
+		n.Val = *new(T)
+		return nil
+	}
+	if err := value.Decode(&n.Val); err != nil {
+		return err
+	}
+	n.NotNil = true
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// Use the omitempty tag option to omit unset values from the output.
+func (o Opt[T]) MarshalYAML() (any, error) {
+	if !o.Set {
+		return nil, nil
+	}
+	return o.Val, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// Any present value marks the Opt as set, while absent keys leave it unset.
+// Note that yaml.v3 does not call it for null nodes, so a null value is treated as absent,
+// use the UnmarshalYAML function to decode them as set.
+func (o *Opt[T]) UnmarshalYAML(value *yaml.Node) error {
+	o.Set = false
+	if err := value.Decode(&o.Val); err != nil {
+		return err
+	}
+	o.Set = true
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (d Dyn) MarshalYAML() (any, error) {
+	return d.Val, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// The decoded value is converted to the same shapes JSON produces (map[string]any, []any, float64, etc),
+// so that Dyn values compare equal regardless of their source. Timestamps are kept as strings holding
+// their original text, since JSON has no time type either.
+func (d *Dyn) UnmarshalYAML(value *yaml.Node) error {
+	var val any
+	if err := yamlTimestampsAsStrings(value, map[*yaml.Node]*yaml.Node{}).Decode(&val); err != nil {
+		return err
+	}
+	d.Val = convertYAMLToJSON(val)
+	return nil
+}
+
+// applyYAMLNulls walks n along with the value decoded from it and applies its null nodes with applyYAMLNull.
+func applyYAMLNulls(n *yaml.Node, v reflect.Value) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) > 0 {
+			applyYAMLNulls(n.Content[0], v)
+		}
+		return
+	case yaml.AliasNode:
+		if n.Alias != nil {
+			applyYAMLNulls(n.Alias, v)
+		}
+		return
+	}
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null" {
+		applyYAMLNull(v)
+		return
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if !v.CanAddr() {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		switch v.Addr().Interface().(type) {
+		case optionalTarget, nullableTarget:
+			applyYAMLNulls(n, v.Field(0))
+			return
+		case yaml.Unmarshaler:
+			return // it decodes the node on its own
+		}
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		fields := yamlFieldsOf(v.Type())
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			index, ok := fields[n.Content[i].Value]
+			if !ok {
+				continue
+			}
+			if fv, err := fieldByIndexAlloc(v, index); err == nil {
+				applyYAMLNulls(n.Content[i+1], fv)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if n.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range n.Content {
+			if i < v.Len() {
+				applyYAMLNulls(item, v.Index(i))
+			}
+		}
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := reflect.New(v.Type().Key())
+			if err := n.Content[i].Decode(key.Interface()); err != nil {
+				continue
+			}
+			elem := v.MapIndex(key.Elem())
+			if !elem.IsValid() {
+				continue
+			}
+			item := reflect.New(elem.Type()).Elem()
+			item.Set(elem)
+			applyYAMLNulls(n.Content[i+1], item)
+			v.SetMapIndex(key.Elem(), item)
+		}
+	}
+}
+
+// applyYAMLNull resets a Nil or Dyn and marks an Opt as set holding the null value of its type.
+// Other values are left untouched, as yaml.v3 does.
+func applyYAMLNull(v reflect.Value) {
+	if !v.CanAddr() || !v.CanSet() {
+		return
+	}
+	switch target := v.Addr().Interface().(type) {
+	case optionalTarget:
+		v.SetZero()
+		applyYAMLNull(reflect.ValueOf(target.setTarget()).Elem())
+	case nullableTarget, *Dyn:
+		v.SetZero()
+	}
+}
+
+// yamlFieldsOf returns the index of the struct fields of t by their yaml.v3 keys:
+// the name in the `yaml` tag or the lowercased field name, including the fields of inlined structs.
+func yamlFieldsOf(t reflect.Type) map[string][]int {
+	fields := map[string][]int{}
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if hasTagOption(opts, "inline") {
+			inline := sf.Type
+			if inline.Kind() == reflect.Pointer {
+				inline = inline.Elem()
+			}
+			if inline.Kind() == reflect.Struct {
+				for key, index := range yamlFieldsOf(inline) {
+					fields[key] = append([]int{i}, index...)
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+		fields[name] = []int{i}
+	}
+	return fields
+}
+
+// yamlTimestampsAsStrings returns a copy of n whose timestamp scalars are tagged as strings,
+// so that they are decoded as their original text rather than as time.Time values.
+// The copies of nodes are kept in copies, so that aliases refer to the copies of their anchors.
+func yamlTimestampsAsStrings(n *yaml.Node, copies map[*yaml.Node]*yaml.Node) *yaml.Node {
+	if c, ok := copies[n]; ok {
+		return c
+	}
+	c := *n
+	copies[n] = &c
+	if c.Kind == yaml.ScalarNode && c.ShortTag() == "!!timestamp" {
+		c.Tag = "!!str"
+	}
+	if c.Alias != nil {
+		c.Alias = yamlTimestampsAsStrings(c.Alias, copies)
+	}
+	if len(n.Content) > 0 {
+		c.Content = make([]*yaml.Node, len(n.Content))
+		for i, item := range n.Content {
+			c.Content[i] = yamlTimestampsAsStrings(item, copies)
+		}
+	}
+	return &c
+}
+
+// convertYAMLToJSON converts YAML decoded types to the types produced by encoding/json.
+func convertYAMLToJSON(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(val))
+		for k, item := range val {
+			m[k] = convertYAMLToJSON(item)
+		}
+		return m
+	case map[any]any:
+		m := make(map[string]any, len(val))
+		for k, item := range val {
+			m[fmt.Sprint(k)] = convertYAMLToJSON(item)
+		}
+		return m
+	case []any:
+		arr := make([]any, len(val))
+		for i, item := range val {
+			arr[i] = convertYAMLToJSON(item)
+		}
+		return arr
+	case int:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case uint64:
+		return float64(val)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,167 @@
+package typx_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pedramktb/go-typx"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type yamlUser struct {
+	Name     typx.Opt[string]           `yaml:"name,omitempty"`
+	Landline typx.Nil[string]           `yaml:"landline"`
+	Fax      typx.Opt[typx.Nil[string]] `yaml:"fax,omitempty"`
+	Info     typx.Dyn                   `yaml:"info"`
+}
+
+func Test_YAML_Marshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value yamlUser
+		want  string
+	}{
+		{
+			name:  "empty",
+			value: yamlUser{},
+			want:  "landline: null\ninfo: null\n",
+		},
+		{
+			name: "full",
+			value: yamlUser{
+				Name:     typx.OptFrom("name"),
+				Landline: typx.NilFrom("123"),
+				Fax:      typx.OptFrom(typx.Nil[string]{}),
+				Info:     typx.Dyn{Val: map[string]any{"a": []any{1, "b"}}},
+			},
+			want: "name: name\nlandline: \"123\"\nfax: null\ninfo:\n    a:\n        - 1\n        - b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yaml.Marshal(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_YAML_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  yamlUser
+	}{
+		{
+			name:  "absent",
+			value: "{}",
+			want:  yamlUser{},
+		},
+		{
+			name:  "null",
+			value: "landline: ~\nfax: ~\ninfo: null\n",
+			want:  yamlUser{Fax: typx.OptFrom(typx.Nil[string]{})},
+		},
+		{
+			name:  "values",
+			value: "name: name\nlandline: \"123\"\nfax: \"456\"\ninfo:\n  a: [1, b]\n",
+			want: yamlUser{
+				Name:     typx.OptFrom("name"),
+				Landline: typx.NilFrom("123"),
+				Fax:      typx.OptFrom(typx.NilFrom("456")),
+				Info:     typx.Dyn{Val: map[string]any{"a": []any{float64(1), "b"}}},
+			},
+		},
+		{
+			name:  "empty string is not null",
+			value: "name: \"\"\n",
+			want:  yamlUser{Name: typx.OptFrom("")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got yamlUser
+			assert.NoError(t, typx.UnmarshalYAML([]byte(tt.value), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_YAML_Unmarshal_ResetsNull(t *testing.T) {
+	got := yamlUser{
+		Landline: typx.NilFrom("123"),
+		Fax:      typx.OptFrom(typx.NilFrom("456")),
+		Info:     typx.Dyn{Val: "info"},
+	}
+	assert.NoError(t, typx.UnmarshalYAML([]byte("landline: ~\nfax: null\ninfo: ~\n"), &got))
+	assert.Equal(t, yamlUser{Fax: typx.OptFrom(typx.Nil[string]{})}, got)
+
+	type nested struct {
+		Users []yamlUser                         `yaml:"users"`
+		ByID  map[string]typx.Opt[typx.Nil[int]] `yaml:"by_id"`
+		Ptr   *yamlUser                          `yaml:"ptr"`
+	}
+	var n nested
+	assert.NoError(t, typx.UnmarshalYAML([]byte("users: [{fax: ~}, {}]\nby_id: {a: ~, b: 1}\nptr: {fax: ~}\n"), &n))
+	assert.Equal(t, nested{
+		Users: []yamlUser{{Fax: typx.OptFrom(typx.Nil[string]{})}, {}},
+		ByID:  map[string]typx.Opt[typx.Nil[int]]{"a": typx.OptFrom(typx.Nil[int]{}), "b": typx.OptFrom(typx.NilFrom(1))},
+		Ptr:   &yamlUser{Fax: typx.OptFrom(typx.Nil[string]{})},
+	}, n)
+
+	assert.Error(t, typx.UnmarshalYAML([]byte("fax: ~"), got))
+}
+
+func Test_YAML_OptNil_RoundTrip(t *testing.T) {
+	type doc struct {
+		Fax typx.Opt[typx.Nil[string]] `yaml:"fax,omitempty"`
+	}
+	tests := []struct {
+		name  string
+		value doc
+		want  string
+	}{
+		{name: "absent", value: doc{}, want: "{}\n"},
+		{name: "null", value: doc{Fax: typx.OptFrom(typx.Nil[string]{})}, want: "fax: null\n"},
+		{name: "value", value: doc{Fax: typx.OptFrom(typx.NilFrom("123"))}, want: "fax: \"123\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := yaml.Marshal(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+			var got doc
+			assert.NoError(t, typx.UnmarshalYAML(data, &got))
+			assert.Equal(t, tt.value, got)
+		})
+	}
+}
+
+func Test_Dyn_YAML_MatchesJSON(t *testing.T) {
+	var fromYAML, fromJSON typx.Dyn
+	assert.NoError(t, yaml.Unmarshal([]byte("a: 1\nb: [true, 2.5, ~]\n3: {x: 2020-01-01T00:00:00Z}\n"), &fromYAML))
+	assert.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":[true,2.5,null],"3":{"x":"2020-01-01T00:00:00Z"}}`), &fromJSON))
+	assert.Equal(t, fromJSON, fromYAML)
+
+	var timestamps typx.Dyn
+	src := "date: 2002-12-14\nspaced: 2001-12-14 21:59:43.10 -5\nexplicit: !!timestamp 2001-12-15T02:59:43.1Z\n" +
+		"anchored: &t 2020-01-01t00:00:00z\nalias: *t\n"
+	assert.NoError(t, yaml.Unmarshal([]byte(src), &timestamps))
+	assert.Equal(t, typx.Dyn{Val: map[string]any{
+		"date":     "2002-12-14",
+		"spaced":   "2001-12-14 21:59:43.10 -5",
+		"explicit": "2001-12-15T02:59:43.1Z",
+		"anchored": "2020-01-01t00:00:00z",
+		"alias":    "2020-01-01t00:00:00z",
+	}}, timestamps)
+	data, err := yaml.Marshal(timestamps)
+	assert.NoError(t, err)
+	var again typx.Dyn
+	assert.NoError(t, yaml.Unmarshal(data, &again))
+	assert.Equal(t, timestamps, again)
+
+	var invalid typx.Nil[int]
+	assert.Error(t, yaml.Unmarshal([]byte("abc"), &invalid))
+	assert.False(t, invalid.NotNil)
+}